@@ -0,0 +1,93 @@
+package fuzzymatcher
+
+import (
+	"sort"
+	"strings"
+)
+
+// OverlapKind describes how two sentences overlap
+type OverlapKind string
+
+const (
+	// OverlapIdentical means both sentences have exactly the same words
+	OverlapIdentical OverlapKind = "identical"
+	// OverlapAmbiguous means the words of both sentences fuzzy match each other but they are not identical, for example "banana" and "banan"
+	OverlapAmbiguous OverlapKind = "ambiguous"
+	// OverlapSubsumed means all words of Sentence fuzzy match words of Other, for example "peer" and "peer review"
+	OverlapSubsumed OverlapKind = "subsumed"
+)
+
+// Overlap describes two sentences where every input that matches Other also matches Sentence
+// Match returns which ever of the two sentences completes first so one of them might never be returned
+type Overlap struct {
+	Kind OverlapKind
+	// Sentence and Other are indexes of sentences within the NewMatcher input
+	// For OverlapIdentical and OverlapAmbiguous Sentence is always lower than Other
+	Sentence int
+	Other    int
+}
+
+// Analyze searches for sentences that overlap with other sentences
+// This matches the words of every sentence against the matcher so it uses the index instead of comparing every sentence with each other
+// The results are ordered by Sentence and Other
+func (m *Matcher) Analyze() []Overlap {
+	texts := make([]string, len(m.Sentences))
+	sentenceByInputIdx := map[int]int{}
+	for idx := range m.Sentences {
+		sentence := &m.Sentences[idx]
+		texts[idx] = strings.Join(m.words(sentence), " ")
+		sentenceByInputIdx[int(sentence.IdxInNewMatcherInput)] = idx
+	}
+
+	// matchedBy contains for every sentence the sentences that matched its text
+	matchedBy := make([]map[int]bool, len(m.Sentences))
+	for idx, text := range texts {
+		matchedBy[idx] = map[int]bool{}
+		for _, match := range m.MatchAll(text) {
+			matchedIdx := sentenceByInputIdx[match.Sentence]
+			if matchedIdx != idx {
+				matchedBy[idx][matchedIdx] = true
+			}
+		}
+	}
+
+	res := []Overlap{}
+	for idx := range m.Sentences {
+		for otherIdx := range matchedBy[idx] {
+			// The words of the sentence at otherIdx are all found in the text of the sentence at idx
+			sentence := int(m.Sentences[otherIdx].IdxInNewMatcherInput)
+			other := int(m.Sentences[idx].IdxInNewMatcherInput)
+
+			if !matchedBy[otherIdx][idx] {
+				res = append(res, Overlap{
+					Kind:     OverlapSubsumed,
+					Sentence: sentence,
+					Other:    other,
+				})
+				continue
+			}
+
+			if sentence > other {
+				// Both sentences match each other, only report this once
+				continue
+			}
+			kind := OverlapAmbiguous
+			if texts[idx] == texts[otherIdx] {
+				kind = OverlapIdentical
+			}
+			res = append(res, Overlap{
+				Kind:     kind,
+				Sentence: sentence,
+				Other:    other,
+			})
+		}
+	}
+
+	sort.Slice(res, func(a, b int) bool {
+		if res[a].Sentence == res[b].Sentence {
+			return res[a].Other < res[b].Other
+		}
+		return res[a].Sentence < res[b].Sentence
+	})
+	return res
+}
@@ -0,0 +1,28 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	m := NewMatcher(
+		"peer review",
+		"peer",
+		"I love trees",
+		"",
+		"i LOVE trees!",
+		"banana",
+		"banan",
+		"apple pie",
+	)
+
+	a.Equal(t, []Overlap{
+		{Kind: OverlapSubsumed, Sentence: 1, Other: 0},
+		{Kind: OverlapIdentical, Sentence: 2, Other: 4},
+		{Kind: OverlapAmbiguous, Sentence: 5, Other: 6},
+	}, m.Analyze())
+
+	a.Equal(t, []Overlap{}, NewMatcher("banana", "apple").Analyze())
+}
@@ -0,0 +1,177 @@
+package fuzzymatcher
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchCancelCheckInterval is the amount of inputs a worker matches before checking if the context was canceled
+const batchCancelCheckInterval = 64
+
+func batchWorkers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// MatchBatch matches all inputs using multiple goroutines that share this matcher
+// Returns the result of Match for every input in the same order as the inputs
+// If workers is 0 or lower runtime.GOMAXPROCS(0) workers are used
+func (m *Matcher) MatchBatch(inputs []string, workers int) []int {
+	res, _ := m.MatchBatchContext(context.Background(), inputs, workers)
+	return res
+}
+
+// MatchBatchContext is the same as MatchBatch but stops when the context is canceled
+// If the context was canceled the context error is returned and the inputs that where not matched are set to -1
+func (m *Matcher) MatchBatchContext(ctx context.Context, inputs []string, workers int) ([]int, error) {
+	res := make([]int, len(inputs))
+	workers = batchWorkers(workers)
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			defer wg.Done()
+
+			state := newMatchState(m)
+			for matched := 0; ; matched++ {
+				if matched%batchCancelCheckInterval == 0 && ctx.Err() != nil {
+					break
+				}
+
+				idx := int(atomic.AddInt64(&next, 1))
+				if idx >= len(inputs) {
+					break
+				}
+				res[idx] = state.first(inputs[idx])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// When all inputs are matched next is equal or higher than the amount of inputs
+	notMatched := int(atomic.LoadInt64(&next)) + 1
+	if notMatched >= len(inputs) {
+		return res, nil
+	}
+	for idx := notMatched; idx < len(inputs); idx++ {
+		res[idx] = -1
+	}
+	return res, ctx.Err()
+}
+
+// BatchResult is the result of matching one input send over a channel
+type BatchResult struct {
+	// Index is the position of the input in the input channel
+	Index int
+	Input string
+	// Match is the result of Match for the input
+	Match int
+}
+
+type batchJob struct {
+	Index int
+	Input string
+}
+
+// MatchChan matches every input received from the inputs channel using multiple goroutines that share this matcher
+// The results are send in the same order as the inputs where received
+// The returned channel is closed after the inputs channel is closed and all inputs are matched or when the context is canceled
+// If workers is 0 or lower runtime.GOMAXPROCS(0) workers are used
+func (m *Matcher) MatchChan(ctx context.Context, inputs <-chan string, workers int) <-chan BatchResult {
+	workers = batchWorkers(workers)
+
+	// window limits the amount of inputs that can be matched ahead of the oldest input that has not yet been send
+	// so a slow input does not cause the other results to pile up
+	window := make(chan struct{}, workers*16)
+	jobs := make(chan batchJob, workers)
+	results := make(chan BatchResult, workers)
+	out := make(chan BatchResult, workers)
+
+	go func() {
+		defer close(jobs)
+		idx := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case window <- struct{}{}:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case input, ok := <-inputs:
+				if !ok {
+					return
+				}
+				jobs <- batchJob{Index: idx, Input: input}
+				idx++
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			defer wg.Done()
+
+			state := newMatchState(m)
+			for job := range jobs {
+				result := BatchResult{
+					Index: job.Index,
+					Input: job.Input,
+					Match: state.first(job.Input),
+				}
+				select {
+				case <-ctx.Done():
+				case results <- result:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(out)
+
+		// Put the results back in the order of the inputs
+		pending := map[int]BatchResult{}
+		next := 0
+		for result := range results {
+			pending[result.Index] = result
+			for {
+				nextResult, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+
+				select {
+				case <-ctx.Done():
+					// Drain the remaining results so the workers can exit
+					for range results {
+					}
+					return
+				case out <- nextResult:
+					<-window
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,112 @@
+package fuzzymatcher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func batchTestInputs() []string {
+	inputs := []string{}
+	for i := 0; i < 1000; i++ {
+		switch i % 4 {
+		case 0:
+			inputs = append(inputs, "nothing")
+		case 1:
+			inputs = append(inputs, fmt.Sprintf("do you also love trees? %d", i))
+		case 2:
+			inputs = append(inputs, "bananas are the best fruit")
+		case 3:
+			inputs = append(inputs, "on a sunday afternoon i like to eat a banana")
+		}
+	}
+	return inputs
+}
+
+func TestMatchBatch(t *testing.T) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+		"love trees",
+	)
+	inputs := batchTestInputs()
+
+	expected := []int{}
+	for _, input := range inputs {
+		expected = append(expected, m.Match(input))
+	}
+
+	a.Equal(t, expected, m.MatchBatch(inputs, 4))
+	a.Equal(t, expected, m.MatchBatch(inputs, 0))
+	a.Equal(t, []int{}, m.MatchBatch([]string{}, 4))
+}
+
+func TestMatchBatchContextCanceled(t *testing.T) {
+	m := NewMatcher("banana")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := m.MatchBatchContext(ctx, []string{"banana", "banana"}, 2)
+	a.Equal(t, context.Canceled, err)
+	a.Equal(t, []int{-1, -1}, res)
+}
+
+func TestMatchChan(t *testing.T) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+	)
+	inputs := batchTestInputs()
+
+	inputsChan := make(chan string)
+	go func() {
+		for _, input := range inputs {
+			inputsChan <- input
+		}
+		close(inputsChan)
+	}()
+
+	idx := 0
+	for result := range m.MatchChan(context.Background(), inputsChan, 4) {
+		a.Equal(t, idx, result.Index)
+		a.Equal(t, inputs[idx], result.Input)
+		a.Equal(t, m.Match(inputs[idx]), result.Match)
+		idx++
+	}
+	a.Equal(t, len(inputs), idx)
+}
+
+func TestMatchChanCanceled(t *testing.T) {
+	m := NewMatcher("banana")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	inputsChan := make(chan string)
+	results := m.MatchChan(ctx, inputsChan, 2)
+
+	inputsChan <- "banana"
+	a.Equal(t, 0, (<-results).Match)
+
+	cancel()
+	for range results {
+	}
+}
+
+func BenchmarkMatchBatch(b *testing.B) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+		"我 在 和",
+	)
+	inputs := batchTestInputs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.MatchBatch(inputs, 0)
+	}
+}
@@ -0,0 +1,79 @@
+package fuzzymatcher
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The benchmarks below use the corpora in testdata/bench, see corpus_test.go
+// Their names are in the key=value format so they can be compared using benchstat:
+//
+//	go test -run xxx -bench Corpus -count 10 . > old.txt
+//	go test -run xxx -bench Corpus -count 10 . > new.txt
+//	benchstat old.txt new.txt
+
+var (
+	benchCorpusSizes = []int{100, 1_000, 10_000, 100_000, 1_000_000}
+	// benchHitPercentages are the percentages of the queries of a BenchmarkCorpusMatch op that match a sentence
+	benchHitPercentages = []int{0, 10, 100}
+)
+
+// benchSizes returns the corpus sizes to benchmark, the largest size is skipped with -short
+func benchSizes() []int {
+	if testing.Short() {
+		return benchCorpusSizes[:len(benchCorpusSizes)-1]
+	}
+	return benchCorpusSizes
+}
+
+func BenchmarkCorpusNewMatcher(b *testing.B) {
+	for _, corpus := range benchCorpora {
+		for _, size := range benchSizes() {
+			b.Run(fmt.Sprintf("corpus=%s/size=%d", corpus.Name, size), func(b *testing.B) {
+				sentences := loadBenchCorpus(b, corpus.Name, size).Sentences
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					NewMatcher(sentences...)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkCorpusMatch matches 100 queries per op of which the hits percentage match a sentence
+// The throughput in MB/s is the amount of query bytes matched per second
+func BenchmarkCorpusMatch(b *testing.B) {
+	for _, corpus := range benchCorpora {
+		for _, size := range benchSizes() {
+			// The corpus is only loaded if one of its sub benchmarks is selected by -bench
+			b.Run(fmt.Sprintf("corpus=%s/size=%d", corpus.Name, size), func(b *testing.B) {
+				files := loadBenchCorpus(b, corpus.Name, size)
+				m := NewMatcher(files.Sentences...)
+
+				for _, hits := range benchHitPercentages {
+					queries := make([]string, 100)
+					queryBytes := 0
+					for idx := range queries {
+						if idx < hits {
+							queries[idx] = files.Hits[idx]
+						} else {
+							queries[idx] = files.Misses[idx]
+						}
+						queryBytes += len(queries[idx])
+					}
+
+					b.Run(fmt.Sprintf("hits=%d", hits), func(b *testing.B) {
+						b.ReportAllocs()
+						b.SetBytes(int64(queryBytes))
+						for i := 0; i < b.N; i++ {
+							for _, query := range queries {
+								m.Match(query)
+							}
+						}
+					})
+				}
+			})
+		}
+	}
+}
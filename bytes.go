@@ -0,0 +1,26 @@
+package fuzzymatcher
+
+import (
+	"unsafe"
+)
+
+// MatchBytes is the same as Match but takes a byte slice as input
+// The input is matched without copying it to a string so this does not allocate
+func (m *Matcher) MatchBytes(sentence []byte) int {
+	return m.Match(bytesToString(sentence))
+}
+
+// MatchAllBytes is the same as MatchAll but takes a byte slice as input
+// The input is matched without copying it to a string, only the returned slice is allocated
+func (m *Matcher) MatchAllBytes(sentence []byte) []Match {
+	return m.MatchAll(bytesToString(sentence))
+}
+
+// bytesToString returns a string that shares its memory with b
+// The returned string is only valid as long as b is not modified so it should never be kept around
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,77 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+var bytesTestInputs = [][]byte{
+	[]byte("nothing"),
+	[]byte("i love trees"),
+	[]byte("bananas are the best fruit"),
+	[]byte("on a sunday afternoon i like to eat a banana"),
+	[]byte("the coördinator said 我 在 和"),
+	{},
+}
+
+func newBytesTestMatcher() *Matcher {
+	return NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+		"coordinator",
+		"我 在 和",
+	)
+}
+
+func TestMatchBytes(t *testing.T) {
+	m := newBytesTestMatcher()
+	for _, input := range bytesTestInputs {
+		a.Equal(t, m.Match(string(input)), m.MatchBytes(input), string(input))
+		a.Equal(t, m.MatchAll(string(input)), m.MatchAllBytes(input), string(input))
+	}
+}
+
+func TestMatchBytesDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	m := newBytesTestMatcher()
+	// Warm up the zero alloc cache
+	for _, input := range bytesTestInputs {
+		m.MatchBytes(input)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, input := range bytesTestInputs {
+			m.MatchBytes(input)
+		}
+	})
+	a.Equal(t, 0.0, allocs)
+}
+
+func BenchmarkMatchBytes(b *testing.B) {
+	m := newBytesTestMatcher()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, input := range bytesTestInputs {
+			m.MatchBytes(input)
+		}
+	}
+}
+
+func BenchmarkMatchAllBytes(b *testing.B) {
+	m := newBytesTestMatcher()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, input := range bytesTestInputs {
+			m.MatchAllBytes(input)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package fuzzymatcher
+
+import "math/bits"
+
+// DefaultClusterThreshold is used by ClusterItems if ClusterOptions.Threshold is not set
+const DefaultClusterThreshold = 0.75
+
+// ClusterOptions changes the behavior of ClusterItems
+type ClusterOptions struct {
+	// Threshold is the minimum similarity between 0 and 1 two items need to be put in the same cluster, defaults to DefaultClusterThreshold
+	Threshold float64
+	// Engine is the engine used to compare the words of the items
+	Engine Engine
+}
+
+// Cluster is a group of items that are near duplicates of each other
+type Cluster struct {
+	// Representative is the index of the member that is the most similar to the other members
+	Representative int
+	// Members contains the indexes of the items in this cluster in ascending order, this includes the representative
+	Members []int
+}
+
+// clusterPair contains for two items how many words of each item are matched by the other item
+type clusterPair struct {
+	// AMatched is the amount of words of the item with the lowest index that are matched by the other item
+	AMatched int
+	BMatched int
+}
+
+// ClusterItems groups items that are near duplicates, for example the same product listed with slightly different spellings
+// The similarity of two items is the fraction of the words of both items that fuzzy match a word of the other item,
+// words are compared the same way as Match does so "banana" and "banan" are the same word
+// Items are put in the same cluster if they have a similarity of at least the threshold with one of the other members
+// Every item is matched against a matcher of all items so only items that share words are compared instead of comparing every item with each other
+// Every item is part of exactly one cluster, items without a near duplicate are a cluster on their own
+// The clusters are ordered by their first member so the representatives of all clusters form the deduplicated items
+func ClusterItems(items []string, opts ClusterOptions) []Cluster {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultClusterThreshold
+	}
+
+	m := NewMatcher(items...)
+	m.Engine = opts.Engine
+
+	// words contains the amount of words of every item that can be matched
+	words := make([]int, len(items))
+	for idx := range m.Sentences {
+		sentence := &m.Sentences[idx]
+		words[sentence.IdxInNewMatcherInput] = bits.OnesCount64(sentence.indexSum())
+	}
+
+	pairs := map[[2]int]*clusterPair{}
+	state := m.getState()
+	for item, text := range items {
+		if words[item] == 0 {
+			continue
+		}
+
+		state.walk(text, false, func(entry *inProgressMatch, p posting, start, end int) bool {
+			state.addWordIdxToSentence(entry, p, start, end)
+			return true
+		})
+
+		for _, sentenceIdx := range state.touched {
+			other := int(m.Sentences[sentenceIdx].IdxInNewMatcherInput)
+			if other == item {
+				continue
+			}
+			matched := bits.OnesCount64(state.Sentences[sentenceIdx].IndexSum)
+			// At most all words of this item can be matched by the other item
+			if float64(matched+words[item])/float64(words[item]+words[other]) < threshold {
+				continue
+			}
+
+			key := [2]int{item, other}
+			if other < item {
+				key = [2]int{other, item}
+			}
+			pair, ok := pairs[key]
+			if !ok {
+				pair = &clusterPair{}
+				pairs[key] = pair
+			}
+			if other == key[0] {
+				pair.AMatched = matched
+			} else {
+				pair.BMatched = matched
+			}
+		}
+	}
+	m.putState(state)
+
+	// Join the items of every pair that is similar enough, parent points to an item of the same cluster
+	parent := make([]int, len(items))
+	for idx := range parent {
+		parent[idx] = idx
+	}
+	root := func(idx int) int {
+		for parent[idx] != idx {
+			parent[idx] = parent[parent[idx]]
+			idx = parent[idx]
+		}
+		return idx
+	}
+	// similarity contains for every item the sum of the similarities with the items it's joined with
+	similarity := make([]float64, len(items))
+	for key, pair := range pairs {
+		pairSimilarity := float64(pair.AMatched+pair.BMatched) / float64(words[key[0]]+words[key[1]])
+		if pairSimilarity < threshold {
+			continue
+		}
+		similarity[key[0]] += pairSimilarity
+		similarity[key[1]] += pairSimilarity
+
+		a, b := root(key[0]), root(key[1])
+		if a != b {
+			if b < a {
+				a, b = b, a
+			}
+			parent[b] = a
+		}
+	}
+
+	clusterByRoot := map[int]int{}
+	res := []Cluster{}
+	for item := range items {
+		itemRoot := root(item)
+		clusterIdx, ok := clusterByRoot[itemRoot]
+		if !ok {
+			clusterIdx = len(res)
+			clusterByRoot[itemRoot] = clusterIdx
+			res = append(res, Cluster{Representative: item})
+		}
+
+		cluster := &res[clusterIdx]
+		cluster.Members = append(cluster.Members, item)
+		if similarity[item] > similarity[cluster.Representative] {
+			cluster.Representative = item
+		}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package fuzzymatcher
+
+import (
+	"fmt"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestClusterItems(t *testing.T) {
+	items := []string{
+		"Apple iPhone 12 64GB Black",
+		"Samsung Galaxy S21",
+		"apple iphone 12 64gb black",
+		"Apple iPhone 12 64 GB Black",
+		"Aple iPhone 12 64GB Blak",
+		"Samsung Galaxy S21 Ultra",
+		"Banana",
+		"",
+		"Apple iPhone 12 64GB Black",
+	}
+
+	clusters := ClusterItems(items, ClusterOptions{})
+	a.Equal(t, []Cluster{
+		{Representative: 0, Members: []int{0, 2, 4, 8}},
+		{Representative: 1, Members: []int{1, 5}},
+		{Representative: 3, Members: []int{3}},
+		{Representative: 6, Members: []int{6}},
+		{Representative: 7, Members: []int{7}},
+	}, clusters)
+
+	// "64 GB" only matches the "64" of "64GB" so this needs a lower threshold
+	clusters = ClusterItems(items, ClusterOptions{Threshold: 0.7})
+	a.Equal(t, []int{0, 2, 3, 4, 8}, clusters[0].Members)
+
+	// With a higher threshold the extra word of the ultra and the typos are to much
+	clusters = ClusterItems(items, ClusterOptions{Threshold: 0.9})
+	a.Equal(t, []int{0, 2, 8}, clusters[0].Members)
+	a.Equal(t, []int{1}, clusters[1].Members)
+	a.Len(t, clusters, 7)
+
+	clusters = ClusterItems(items, ClusterOptions{Engine: EngineTrie})
+	a.Equal(t, []int{0, 2, 4, 8}, clusters[0].Members)
+
+	a.Equal(t, []Cluster{}, ClusterItems(nil, ClusterOptions{}))
+}
+
+func TestClusterItemsRepresentative(t *testing.T) {
+	clusters := ClusterItems([]string{
+		"fresh banana from spain",
+		"banana from spain",
+		"banana from spain today",
+	}, ClusterOptions{})
+	a.Len(t, clusters, 1)
+	a.Equal(t, 1, clusters[0].Representative, "the most similar to the others")
+}
+
+func BenchmarkClusterItems(b *testing.B) {
+	sentences := benchmarkSentences(10_000)
+	// Add a near duplicate of every tenth sentence
+	items := append([]string{}, sentences...)
+	for idx := 0; idx < len(sentences); idx += 10 {
+		items = append(items, fmt.Sprintf("%s x", sentences[idx]))
+	}
+
+	for i := 0; i < b.N; i++ {
+		ClusterItems(items, ClusterOptions{})
+	}
+}
@@ -0,0 +1,75 @@
+// Command fuzzymatch-server serves fuzzy matchers over HTTP with JSON requests and responses
+//
+// Every set of sentences is loaded from a file with one sentence per line, blank lines are skipped:
+//
+//	fuzzymatch-server -set products=products.txt -set cities=cities.txt
+//
+// Endpoints, all of them only accept POST requests with a JSON body:
+//
+//	/match        {"set": "products", "input": "..."}           the first matching sentence
+//	/match/all    {"set": "products", "input": "..."}           all matching sentences
+//	/match/topk   {"set": "products", "input": "...", "k": 5}   the k best matching sentences
+//	/suggest      {"set": "products", "word": "...", "n": 5}    words close to the word
+//	/admin/add    {"set": "products", "sentences": ["..."]}     adds sentences, creates the set if it does not exist
+//	/admin/remove {"set": "products", "sentences": ["..."]}     removes sentences
+//	/admin/reload {"set": "products"}                           reloads the file of a set or of all sets if set is empty
+//
+// If set is empty the set named "default" is used, a -set flag without a name also creates the default set
+// Inputs and words longer than -max-input are rejected with status 413
+// The admin endpoints require the -admin-token as bearer token if it's set
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+// setFlags collects the -set flags
+type setFlags []string
+
+func (f *setFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *setFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	sets := setFlags{}
+	flag.Var(&sets, "set", "a set of sentences to load as name=path or path for the default set, can be used multiple times")
+	addr := flag.String("addr", "localhost:8080", "the address to listen on")
+	adminToken := flag.String("admin-token", "", "the bearer token required by the admin endpoints")
+	maxInput := flag.Int("max-input", 64*1024, "the maximum input length in bytes, 0 for no limit")
+	maxBody := flag.Int64("max-body", 1<<20, "the maximum request body size in bytes")
+	strict := flag.Bool("strict", false, "also fail on sentences that only have warnings")
+	flag.Parse()
+
+	s := newServer(serverOptions{
+		CompileOptions: fuzzymatcher.CompileOptions{Strict: *strict},
+		Limits:         fuzzymatcher.Limits{MaxInputBytes: *maxInput},
+		AdminToken:     *adminToken,
+		MaxBodyBytes:   *maxBody,
+	})
+
+	for _, set := range sets {
+		name, path := defaultSet, set
+		if idx := strings.Index(set, "="); idx != -1 {
+			name, path = set[:idx], set[idx+1:]
+		}
+		err := s.loadFile(name, path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("loaded set %q from %s\n", name, path)
+	}
+
+	fmt.Printf("listening on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+// defaultSet is used by requests that do not specify a set
+const defaultSet = "default"
+
+// patternSet is a named list of sentences, it's never modified after it's created so it can be used without locking
+type patternSet struct {
+	// Path is the file the sentences where loaded from, empty if the set was created using the admin endpoints
+	Path      string
+	Sentences []string
+	Matcher   *fuzzymatcher.Matcher
+}
+
+type serverOptions struct {
+	CompileOptions fuzzymatcher.CompileOptions
+	Limits         fuzzymatcher.Limits
+	// AdminToken is required as bearer token by the admin endpoints if set
+	AdminToken   string
+	MaxBodyBytes int64
+}
+
+type server struct {
+	opts serverOptions
+	mux  *http.ServeMux
+
+	// mu guards sets and updating, the sets are replaced instead of modified
+	mu   sync.RWMutex
+	sets map[string]*patternSet
+	// updating contains a mutex per set that is held while a new version of the set is build,
+	// updates of the same set can not overwrite each other while the other sets can still be used and updated
+	updating map[string]*sync.Mutex
+}
+
+func newServer(opts serverOptions) *server {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 1 << 20
+	}
+	s := &server{
+		opts:     opts,
+		mux:      http.NewServeMux(),
+		sets:     map[string]*patternSet{},
+		updating: map[string]*sync.Mutex{},
+	}
+
+	s.mux.HandleFunc("/match", s.post(s.handleMatch))
+	s.mux.HandleFunc("/match/all", s.post(s.handleMatchAll))
+	s.mux.HandleFunc("/match/topk", s.post(s.handleMatchTopK))
+	s.mux.HandleFunc("/suggest", s.post(s.handleSuggest))
+	s.mux.HandleFunc("/admin/add", s.admin(s.handleAdd))
+	s.mux.HandleFunc("/admin/remove", s.admin(s.handleRemove))
+	s.mux.HandleFunc("/admin/reload", s.admin(s.handleReload))
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// httpError is an error with the status code it should be returned with
+type httpError struct {
+	Status int
+	Err    error
+}
+
+func (e *httpError) Error() string {
+	return e.Err.Error()
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	// Diagnostics is set if the sentences could not be compiled
+	Diagnostics []string `json:"diagnostics,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	res := errorResponse{Error: err.Error()}
+
+	var httpErr *httpError
+	var compileErr *fuzzymatcher.CompileError
+	var limitErr *fuzzymatcher.LimitError
+	switch {
+	case errors.As(err, &httpErr):
+		status = httpErr.Status
+	case errors.As(err, &compileErr):
+		status = http.StatusBadRequest
+		for _, diagnostic := range compileErr.Diagnostics {
+			res.Diagnostics = append(res.Diagnostics, diagnostic.String())
+		}
+	case errors.As(err, &limitErr):
+		status = http.StatusRequestEntityTooLarge
+	}
+	writeJSON(w, status, res)
+}
+
+// post turns a handler that decodes the request body into a http.HandlerFunc that only accepts POST requests
+func (s *server) post(handler func(r *http.Request, decode func(value interface{}) error) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method))
+			return
+		}
+
+		decode := func(value interface{}) error {
+			decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes))
+			decoder.DisallowUnknownFields()
+			err := decoder.Decode(value)
+			if err != nil {
+				return errorf(http.StatusBadRequest, "invalid request body: %s", err)
+			}
+			return nil
+		}
+
+		res, err := handler(r, decode)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// admin is the same as post but also checks the admin token
+func (s *server) admin(handler func(r *http.Request, decode func(value interface{}) error) (interface{}, error)) http.HandlerFunc {
+	post := s.post(handler)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.AdminToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.AdminToken)) != 1 {
+				writeError(w, errorf(http.StatusUnauthorized, "invalid admin token"))
+				return
+			}
+		}
+		post(w, r)
+	}
+}
+
+// set returns the set with the name, an empty name means the default set
+func (s *server) set(name string) (*patternSet, error) {
+	if name == "" {
+		name = defaultSet
+	}
+
+	s.mu.RLock()
+	set, ok := s.sets[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "set %q does not exist", name)
+	}
+	return set, nil
+}
+
+// newPatternSet compiles the sentences into a new set
+func (s *server) newPatternSet(path string, sentences []string) (*patternSet, error) {
+	m, err := fuzzymatcher.Compile(sentences, s.opts.CompileOptions)
+	if err != nil {
+		return nil, err
+	}
+	m.Limits = s.opts.Limits
+	return &patternSet{Path: path, Sentences: sentences, Matcher: m}, nil
+}
+
+// readFile compiles a file with one sentence per line into a new set, blank lines are skipped
+func (s *server) readFile(path string) (*patternSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.newPatternSet(path, fuzzymatcher.SplitSentences(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
+
+// loadFile loads a file with one sentence per line as set
+func (s *server) loadFile(name string, path string) error {
+	_, err := s.update(name, func(set *patternSet) (*patternSet, int, error) {
+		newSet, err := s.readFile(path)
+		return newSet, 0, err
+	})
+	return err
+}
+
+// lockSet locks the update mutex of a set and returns the function to unlock it
+func (s *server) lockSet(name string) func() {
+	s.mu.Lock()
+	mu, ok := s.updating[name]
+	if !ok {
+		mu = &sync.Mutex{}
+		s.updating[name] = mu
+	}
+	s.mu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+type matchRequest struct {
+	Set   string `json:"set"`
+	Input string `json:"input"`
+	// K is the maximum amount of matches returned by /match/topk
+	K int `json:"k"`
+}
+
+type sentenceResult struct {
+	Index    int    `json:"index"`
+	Sentence string `json:"sentence"`
+}
+
+type matchResult struct {
+	sentenceResult
+	Score float64 `json:"score"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+}
+
+type matchResponse struct {
+	// Match is null if nothing matched
+	Match *sentenceResult `json:"match"`
+}
+
+type matchesResponse struct {
+	Matches []matchResult `json:"matches"`
+}
+
+func matchResults(set *patternSet, matches []fuzzymatcher.Match) matchesResponse {
+	res := matchesResponse{Matches: make([]matchResult, len(matches))}
+	for idx, match := range matches {
+		res.Matches[idx] = matchResult{
+			sentenceResult: sentenceResult{Index: match.Sentence, Sentence: set.Sentences[match.Sentence]},
+			Score:          match.Score,
+			Start:          match.Start,
+			End:            match.End,
+		}
+	}
+	return res
+}
+
+func (s *server) decodeMatchRequest(decode func(value interface{}) error) (matchRequest, *patternSet, error) {
+	req := matchRequest{}
+	err := decode(&req)
+	if err != nil {
+		return req, nil, err
+	}
+	set, err := s.set(req.Set)
+	return req, set, err
+}
+
+func (s *server) handleMatch(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req, set, err := s.decodeMatchRequest(decode)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := set.Matcher.MatchContext(r.Context(), req.Input)
+	if err != nil {
+		return nil, err
+	}
+	if idx == -1 {
+		return matchResponse{}, nil
+	}
+	return matchResponse{Match: &sentenceResult{Index: idx, Sentence: set.Sentences[idx]}}, nil
+}
+
+func (s *server) handleMatchAll(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req, set, err := s.decodeMatchRequest(decode)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := set.Matcher.MatchAllContext(r.Context(), req.Input)
+	if err != nil {
+		return nil, err
+	}
+	return matchResults(set, matches), nil
+}
+
+func (s *server) handleMatchTopK(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req, set, err := s.decodeMatchRequest(decode)
+	if err != nil {
+		return nil, err
+	}
+	if req.K <= 0 {
+		return nil, errorf(http.StatusBadRequest, "k must be higher than 0")
+	}
+
+	matches, err := set.Matcher.MatchAllContext(r.Context(), req.Input)
+	if err != nil {
+		return nil, err
+	}
+	return matchResults(set, topK(matches, req.K)), nil
+}
+
+// topK returns the k matches with the highest score, matches with the same score keep the order of MatchAll
+func topK(matches []fuzzymatcher.Match, k int) []fuzzymatcher.Match {
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Score > matches[b].Score
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+type suggestRequest struct {
+	Set  string `json:"set"`
+	Word string `json:"word"`
+	// N is the maximum amount of suggestions, defaults to 5
+	N int `json:"n"`
+}
+
+type suggestion struct {
+	Word      string `json:"word"`
+	Distance  int    `json:"distance"`
+	Frequency int    `json:"frequency"`
+}
+
+type suggestResponse struct {
+	Suggestions []suggestion `json:"suggestions"`
+}
+
+func (s *server) handleSuggest(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req := suggestRequest{}
+	err := decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.set(req.Set)
+	if err != nil {
+		return nil, err
+	}
+	if req.N <= 0 {
+		req.N = 5
+	}
+	if max := s.opts.Limits.MaxInputBytes; max > 0 && len(req.Word) > max {
+		// Suggest compares the word with every word of the set so it's limited like the inputs of the match endpoints
+		return nil, &fuzzymatcher.LimitError{Err: fuzzymatcher.ErrInputTooLarge, Limit: max, Value: len(req.Word)}
+	}
+
+	res := suggestResponse{Suggestions: []suggestion{}}
+	for _, match := range set.Matcher.Suggest(req.Word, req.N) {
+		res.Suggestions = append(res.Suggestions, suggestion{Word: match.Word, Distance: match.Distance, Frequency: match.Frequency})
+	}
+	return res, nil
+}
+
+type adminRequest struct {
+	Set       string   `json:"set"`
+	Sentences []string `json:"sentences"`
+}
+
+type adminResponse struct {
+	Set       string `json:"set"`
+	Sentences int    `json:"sentences"`
+	// Changed is the amount of sentences added or removed
+	Changed int `json:"changed"`
+}
+
+// update replaces a set with the set returned by fn, fn gets nil if the set does not exist yet
+// The update mutex of the set is held while fn runs so updates of the same set can not overwrite each other,
+// mu is only held to swap the set so matching is not blocked while fn compiles the new set
+func (s *server) update(name string, fn func(set *patternSet) (*patternSet, int, error)) (interface{}, error) {
+	if name == "" {
+		name = defaultSet
+	}
+
+	unlock := s.lockSet(name)
+	defer unlock()
+
+	s.mu.RLock()
+	current := s.sets[name]
+	s.mu.RUnlock()
+
+	set, changed, err := fn(current)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.sets[name] = set
+	s.mu.Unlock()
+	return adminResponse{Set: name, Sentences: len(set.Sentences), Changed: changed}, nil
+}
+
+// handleAdd adds sentences to a set, the set is created if it does not exist
+func (s *server) handleAdd(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req := adminRequest{}
+	err := decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.update(req.Set, func(set *patternSet) (*patternSet, int, error) {
+		path := ""
+		sentences := []string{}
+		if set != nil {
+			path = set.Path
+			sentences = append(sentences, set.Sentences...)
+		}
+		sentences = append(sentences, req.Sentences...)
+
+		newSet, err := s.newPatternSet(path, sentences)
+		return newSet, len(req.Sentences), err
+	})
+}
+
+// handleRemove removes every sentence that equals one of the sentences of the request
+func (s *server) handleRemove(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req := adminRequest{}
+	err := decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.update(req.Set, func(set *patternSet) (*patternSet, int, error) {
+		if set == nil {
+			return nil, 0, errorf(http.StatusNotFound, "set %q does not exist", req.Set)
+		}
+
+		remove := map[string]bool{}
+		for _, sentence := range req.Sentences {
+			remove[sentence] = true
+		}
+		sentences := []string{}
+		for _, sentence := range set.Sentences {
+			if !remove[sentence] {
+				sentences = append(sentences, sentence)
+			}
+		}
+
+		newSet, err := s.newPatternSet(set.Path, sentences)
+		return newSet, len(set.Sentences) - len(sentences), err
+	})
+}
+
+type reloadRequest struct {
+	// Set is the set to reload, if empty all sets loaded from a file are reloaded
+	Set string `json:"set"`
+}
+
+type reloadResponse struct {
+	Reloaded []string `json:"reloaded"`
+}
+
+// handleReload reloads sets from their files, changes made using the admin endpoints are lost
+// Either all sets are reloaded or none, if a file can not be loaded the old versions of all sets are kept
+func (s *server) handleReload(r *http.Request, decode func(value interface{}) error) (interface{}, error) {
+	req := reloadRequest{}
+	err := decode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	s.mu.RLock()
+	for name, set := range s.sets {
+		if (req.Set == "" || req.Set == name) && set.Path != "" {
+			names = append(names, name)
+		}
+	}
+	_, exists := s.sets[req.Set]
+	s.mu.RUnlock()
+
+	if req.Set != "" && len(names) == 0 {
+		if !exists {
+			return nil, errorf(http.StatusNotFound, "set %q does not exist", req.Set)
+		}
+		return nil, errorf(http.StatusBadRequest, "set %q is not loaded from a file", req.Set)
+	}
+
+	// The sets are always locked in the same order so concurrent reloads can not wait on each other
+	sort.Strings(names)
+	for _, name := range names {
+		unlock := s.lockSet(name)
+		defer unlock()
+	}
+
+	newSets := make([]*patternSet, len(names))
+	for idx, name := range names {
+		s.mu.RLock()
+		path := s.sets[name].Path
+		s.mu.RUnlock()
+
+		newSets[idx], err = s.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	for idx, name := range names {
+		s.sets[name] = newSets[idx]
+	}
+	s.mu.Unlock()
+	return reloadResponse{Reloaded: names}, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+	a "github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, opts serverOptions) (*httptest.Server, string) {
+	path := filepath.Join(t.TempDir(), "fruit.txt")
+	a.NoError(t, os.WriteFile(path, []byte("banana\nbanana split\napple pie\n"), 0644))
+
+	s := newServer(opts)
+	a.NoError(t, s.loadFile("fruit", path))
+	a.NoError(t, s.loadFile(defaultSet, path))
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts, path
+}
+
+// post sends body as json and decodes the response into a map
+func post(t *testing.T, ts *httptest.Server, path string, body string, headers ...string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(http.MethodPost, ts.URL+path, strings.NewReader(body))
+	a.NoError(t, err)
+	for idx := 0; idx+1 < len(headers); idx += 2 {
+		req.Header.Set(headers[idx], headers[idx+1])
+	}
+	resp, err := ts.Client().Do(req)
+	a.NoError(t, err)
+	defer resp.Body.Close()
+
+	res := map[string]interface{}{}
+	a.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	return resp.StatusCode, res
+}
+
+// toJSON returns the json of value decoded as interface{} so it can be compared to post responses
+func toJSON(t *testing.T, value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	a.NoError(t, err)
+	var res interface{}
+	a.NoError(t, json.NewDecoder(bytes.NewReader(data)).Decode(&res))
+	return res
+}
+
+func TestMatch(t *testing.T) {
+	ts, _ := newTestServer(t, serverOptions{})
+
+	status, res := post(t, ts, "/match", `{"set": "fruit", "input": "i would like a bananna"}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, toJSON(t, map[string]interface{}{"index": 0, "sentence": "banana"}), res["match"])
+
+	status, res = post(t, ts, "/match", `{"input": "nothing"}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Nil(t, res["match"])
+	a.Contains(t, res, "match")
+
+	status, res = post(t, ts, "/match/all", `{"set": "fruit", "input": "a banana split"}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Len(t, res["matches"], 2)
+
+	status, res = post(t, ts, "/match/topk", `{"set": "fruit", "input": "a banana split and a apple pie", "k": 1}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, toJSON(t, []map[string]interface{}{
+		{"index": 0, "sentence": "banana", "score": 1, "start": 2, "end": 8},
+	}), res["matches"])
+
+	status, _ = post(t, ts, "/match/topk", `{"set": "fruit", "input": "banana"}`)
+	a.Equal(t, http.StatusBadRequest, status, "k is required")
+
+	status, res = post(t, ts, "/suggest", `{"set": "fruit", "word": "bananna", "n": 1}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, toJSON(t, []map[string]interface{}{
+		{"word": "banana", "distance": 1, "frequency": 2},
+	}), res["suggestions"])
+}
+
+func TestTopK(t *testing.T) {
+	m := fuzzymatcher.NewMatcher("bananas", "banana", "banana split", "apple")
+	sentences := func(matches []fuzzymatcher.Match) []int {
+		res := []int{}
+		for _, match := range matches {
+			res = append(res, match.Sentence)
+		}
+		return res
+	}
+
+	matches := topK(m.MatchAll("i want a banana split"), 3)
+	a.Equal(t, []int{1, 2, 0}, sentences(matches))
+	a.Equal(t, 1.0, matches[0].Score)
+	a.Equal(t, []int{1, 2}, sentences(topK(m.MatchAll("i want a banana split"), 2)))
+	a.Equal(t, []int{}, sentences(topK(m.MatchAll("nothing"), 2)))
+}
+
+func TestRequestErrors(t *testing.T) {
+	ts, _ := newTestServer(t, serverOptions{
+		Limits:       fuzzymatcher.Limits{MaxInputBytes: 10},
+		MaxBodyBytes: 100,
+	})
+
+	for _, testCase := range []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown set", "/match", `{"set": "vegetables", "input": "banana"}`, http.StatusNotFound},
+		{"invalid json", "/match", `{"input": `, http.StatusBadRequest},
+		{"unknown field", "/match", `{"inptu": "banana"}`, http.StatusBadRequest},
+		{"body too large", "/match", `{"input": "` + strings.Repeat("a", 200) + `"}`, http.StatusBadRequest},
+		{"input too large", "/match/all", `{"input": "a very long banana"}`, http.StatusRequestEntityTooLarge},
+		{"word too large", "/suggest", `{"word": "averylongbanana"}`, http.StatusRequestEntityTooLarge},
+	} {
+		status, res := post(t, ts, testCase.path, testCase.body)
+		a.Equal(t, testCase.status, status, testCase.name)
+		a.NotEmpty(t, res["error"], testCase.name)
+	}
+
+	resp, err := ts.Client().Get(ts.URL + "/match")
+	a.NoError(t, err)
+	resp.Body.Close()
+	a.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAdmin(t *testing.T) {
+	ts, path := newTestServer(t, serverOptions{AdminToken: "secret"})
+	auth := []string{"Authorization", "Bearer secret"}
+
+	status, _ := post(t, ts, "/admin/add", `{"set": "fruit", "sentences": ["pear"]}`)
+	a.Equal(t, http.StatusUnauthorized, status)
+	status, _ = post(t, ts, "/admin/add", `{"set": "fruit", "sentences": ["pear"]}`, "Authorization", "Bearer wrong")
+	a.Equal(t, http.StatusUnauthorized, status)
+
+	status, res := post(t, ts, "/admin/add", `{"set": "fruit", "sentences": ["pear"]}`, auth...)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, toJSON(t, adminResponse{Set: "fruit", Sentences: 4, Changed: 1}), toJSON(t, res))
+	_, res = post(t, ts, "/match", `{"set": "fruit", "input": "a pear"}`)
+	a.Equal(t, "pear", res["match"].(map[string]interface{})["sentence"])
+
+	status, res = post(t, ts, "/admin/add", `{"set": "fruit", "sentences": ["banana"]}`, auth...)
+	a.Equal(t, http.StatusBadRequest, status, "duplicate sentence")
+	a.NotEmpty(t, res["diagnostics"])
+
+	status, res = post(t, ts, "/admin/remove", `{"set": "fruit", "sentences": ["banana", "kiwi"]}`, auth...)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, toJSON(t, adminResponse{Set: "fruit", Sentences: 3, Changed: 1}), toJSON(t, res))
+	_, res = post(t, ts, "/match", `{"set": "fruit", "input": "banana split"}`)
+	a.Equal(t, "banana split", res["match"].(map[string]interface{})["sentence"], "the removed sentence no longer matches")
+
+	status, _ = post(t, ts, "/admin/remove", `{"set": "vegetables", "sentences": ["carrot"]}`, auth...)
+	a.Equal(t, http.StatusNotFound, status)
+
+	// Adding to a set that does not exist creates it
+	status, _ = post(t, ts, "/admin/add", `{"set": "vegetables", "sentences": ["carrot"]}`, auth...)
+	a.Equal(t, http.StatusOK, status)
+	_, res = post(t, ts, "/match", `{"set": "vegetables", "input": "carot"}`)
+	a.NotNil(t, res["match"])
+
+	// Reloading restores the file, blank lines are skipped
+	a.NoError(t, os.WriteFile(path, []byte("banana\n\r\nkiwi\n\n"), 0644))
+	status, res = post(t, ts, "/admin/reload", `{"set": "fruit"}`, auth...)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, []interface{}{"fruit"}, res["reloaded"])
+	_, res = post(t, ts, "/match", `{"set": "fruit", "input": "a kiwi"}`)
+	a.Equal(t, toJSON(t, map[string]interface{}{"index": 1, "sentence": "kiwi"}), res["match"])
+
+	status, res = post(t, ts, "/admin/reload", `{}`, auth...)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, []interface{}{"default", "fruit"}, res["reloaded"])
+
+	status, _ = post(t, ts, "/admin/reload", `{"set": "vegetables"}`, auth...)
+	a.Equal(t, http.StatusBadRequest, status, "the set is not loaded from a file")
+
+	// A invalid file keeps the old version
+	a.NoError(t, os.WriteFile(path, []byte("kiwi\nkiwi\n"), 0644))
+	status, _ = post(t, ts, "/admin/reload", `{"set": "fruit"}`, auth...)
+	a.Equal(t, http.StatusBadRequest, status)
+	_, res = post(t, ts, "/match", `{"set": "fruit", "input": "banana"}`)
+	a.NotNil(t, res["match"])
+}
+
+func TestReloadAllOrNothing(t *testing.T) {
+	ts, fruitPath := newTestServer(t, serverOptions{})
+	_, res := post(t, ts, "/match/all", `{"set": "fruit", "input": "apple pie"}`)
+	a.Len(t, res["matches"], 1)
+
+	// Only the file of the vegetables set becomes invalid
+	s := ts.Config.Handler.(*server)
+	vegetablesPath := filepath.Join(t.TempDir(), "vegetables.txt")
+	a.NoError(t, os.WriteFile(vegetablesPath, []byte("carrot\n"), 0644))
+	a.NoError(t, s.loadFile("vegetables", vegetablesPath))
+
+	a.NoError(t, os.WriteFile(fruitPath, []byte("kiwi\n"), 0644))
+	a.NoError(t, os.WriteFile(vegetablesPath, []byte("carrot\ncarrot\n"), 0644))
+	status, _ := post(t, ts, "/admin/reload", `{}`)
+	a.Equal(t, http.StatusBadRequest, status)
+
+	// None of the sets are reloaded
+	_, res = post(t, ts, "/match/all", `{"set": "fruit", "input": "apple pie"}`)
+	a.Len(t, res["matches"], 1)
+	_, res = post(t, ts, "/match/all", `{"set": "default", "input": "apple pie"}`)
+	a.Len(t, res["matches"], 1)
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	ts, _ := newTestServer(t, serverOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			status, _ := post(t, ts, "/admin/add", fmt.Sprintf(`{"set": "fruit", "sentences": ["fruit %d"]}`, i))
+			a.Equal(t, http.StatusOK, status)
+			status, _ = post(t, ts, "/match", `{"set": "fruit", "input": "banana"}`)
+			a.Equal(t, http.StatusOK, status)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every update is based on the previous one so no sentences are lost
+	status, res := post(t, ts, "/admin/remove", `{"set": "fruit", "sentences": []}`)
+	a.Equal(t, http.StatusOK, status)
+	a.Equal(t, 13.0, res["sentences"])
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+func dedup(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("dedup", flag.ContinueOnError)
+	threshold := flags.Float64("threshold", fuzzymatcher.DefaultClusterThreshold, "the minimum similarity between 0 and 1 of near duplicates")
+	printClusters := flags.Bool("clusters", false, "print all members of every cluster instead of only the representatives")
+	engineFlag := flags.String("engine", "paths", "the engine used to compare words, paths or trie")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	engine, err := parseEngine(*engineFlag)
+	if err != nil {
+		return err
+	}
+	if *threshold <= 0 || *threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1 but got %v", *threshold)
+	}
+
+	in, err := openInput(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	items, err := readLines(in)
+	if err != nil {
+		return err
+	}
+
+	clusters := fuzzymatcher.ClusterItems(items, fuzzymatcher.ClusterOptions{
+		Threshold: *threshold,
+		Engine:    engine,
+	})
+	for idx, cluster := range clusters {
+		if !*printClusters {
+			fmt.Fprintln(stdout, items[cluster.Representative])
+			continue
+		}
+
+		if idx > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintln(stdout, items[cluster.Representative])
+		for _, member := range cluster.Members {
+			if member != cluster.Representative {
+				fmt.Fprintf(stdout, "\t%s\n", items[member])
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+var testItems = strings.Join([]string{
+	"Apple iPhone 13 128GB blue",
+	"apple iphone 13 128gb blue",
+	"Samsung Galaxy S21 black",
+	"Apple iPhone 13 128GB blu",
+	"samsung galaxy s21 blak",
+	"Google Pixel 6",
+}, "\n") + "\n"
+
+func runDedup(t *testing.T, stdin string, args ...string) (string, error) {
+	out := bytes.NewBuffer(nil)
+	err := dedup(args, strings.NewReader(stdin), out)
+	return out.String(), err
+}
+
+func TestDedup(t *testing.T) {
+	out, err := runDedup(t, testItems)
+	a.NoError(t, err)
+	a.Equal(t, "Apple iPhone 13 128GB blue\nSamsung Galaxy S21 black\nGoogle Pixel 6\n", out)
+
+	out, err = runDedup(t, testItems, "-clusters")
+	a.NoError(t, err)
+	a.Equal(t, strings.Join([]string{
+		"Apple iPhone 13 128GB blue",
+		"\tapple iphone 13 128gb blue",
+		"\tApple iPhone 13 128GB blu",
+		"",
+		"Samsung Galaxy S21 black",
+		"\tsamsung galaxy s21 blak",
+		"",
+		"Google Pixel 6",
+	}, "\n")+"\n", out)
+
+	out, err = runDedup(t, testItems, "-engine", "trie")
+	a.NoError(t, err)
+	a.Equal(t, "Apple iPhone 13 128GB blue\nSamsung Galaxy S21 black\nGoogle Pixel 6\n", out)
+
+	out, err = runDedup(t, testItems, "-threshold", "1")
+	a.NoError(t, err)
+	a.Equal(t, 5, strings.Count(out, "\n"), "only items that differ in case are the same")
+
+	path := filepath.Join(t.TempDir(), "items.txt")
+	a.NoError(t, os.WriteFile(path, []byte(testItems), 0644))
+	out, err = runDedup(t, "", path)
+	a.NoError(t, err)
+	a.Equal(t, 3, strings.Count(out, "\n"), "the items are read from the file")
+}
+
+func TestDedupErrors(t *testing.T) {
+	_, err := runDedup(t, "", "-threshold", "2")
+	a.Error(t, err)
+	_, err = runDedup(t, "", "a.txt", "b.txt")
+	a.Error(t, err)
+	_, err = runDedup(t, "", filepath.Join(t.TempDir(), "missing.txt"))
+	a.Error(t, err)
+	_, err = runDedup(t, "", "-engine", "fast")
+	a.Error(t, err)
+}
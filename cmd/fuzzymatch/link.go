@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+// csvTable is a csv file where the first row contains the column names
+type csvTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+func readCSV(path string) (csvTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return csvTable{}, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return csvTable{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return csvTable{}, fmt.Errorf("%s: missing header", path)
+	}
+	return csvTable{Header: rows[0], Rows: rows[1:]}, nil
+}
+
+// column returns the index of the column with the name or -1 if there is no such column
+func (t csvTable) column(name string) int {
+	for idx, column := range t.Header {
+		if column == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// records converts the rows to records with the columns as fields
+func (t csvTable) records(columns []string, required map[string]bool) []fuzzymatcher.Record {
+	res := make([]fuzzymatcher.Record, len(t.Rows))
+	for rowIdx, row := range t.Rows {
+		fields := make([]fuzzymatcher.Field, len(columns))
+		for idx, column := range columns {
+			fields[idx] = fuzzymatcher.Field{Name: column, Required: required[column]}
+			if columnIdx := t.column(column); columnIdx < len(row) {
+				fields[idx].Value = row[columnIdx]
+			}
+		}
+		res[rowIdx] = fuzzymatcher.Record{Fields: fields}
+	}
+	return res
+}
+
+func splitList(value string) []string {
+	res := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func link(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("link", flag.ContinueOnError)
+	threshold := flags.Float64("threshold", fuzzymatcher.DefaultLinkThreshold, "the minimum score between 0 and 1 of a pair")
+	maxCandidates := flags.Int("max", 0, "the maximum amount of pairs per left row, 0 means no limit")
+	fieldsFlag := flags.String("fields", "", "comma separated columns that are compared, defaults to the columns of the left file that are also in the right file")
+	requiredFlag := flags.String("required", "", "comma separated columns that have to match, they must be compared")
+	engineFlag := flags.String("engine", "paths", "the engine used to compare words, paths or trie")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	engine, err := parseEngine(*engineFlag)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("expected a left and right csv file")
+	}
+	if *threshold <= 0 || *threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1 but got %v", *threshold)
+	}
+
+	left, err := readCSV(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	right, err := readCSV(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	columns := splitList(*fieldsFlag)
+	if len(columns) == 0 {
+		for _, column := range left.Header {
+			if right.column(column) != -1 {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) == 0 {
+			return errors.New("the files have no columns in common, use -fields to select them")
+		}
+	}
+	for _, column := range columns {
+		if left.column(column) == -1 || right.column(column) == -1 {
+			return fmt.Errorf("column %q must be in both files", column)
+		}
+	}
+	required := map[string]bool{}
+	for _, column := range splitList(*requiredFlag) {
+		compared := false
+		for _, c := range columns {
+			compared = compared || c == column
+		}
+		if !compared {
+			return fmt.Errorf("required column %q is not one of the compared columns %s", column, strings.Join(columns, ","))
+		}
+		required[column] = true
+	}
+
+	pairs := fuzzymatcher.Link(left.records(columns, required), right.records(columns, nil), fuzzymatcher.LinkOptions{
+		Threshold:     *threshold,
+		MaxCandidates: *maxCandidates,
+		Engine:        engine,
+	})
+
+	// The rows are numbered from 1 and do not include the header
+	w := csv.NewWriter(stdout)
+	header := []string{"left_row", "right_row", "score"}
+	for _, column := range columns {
+		header = append(header, "left_"+column, "right_"+column, column+"_similarity")
+	}
+	w.Write(header)
+	for _, pair := range pairs {
+		row := []string{
+			strconv.Itoa(pair.Left + 1),
+			strconv.Itoa(pair.Right + 1),
+			strconv.FormatFloat(pair.Score, 'f', 3, 64),
+		}
+		for idx, column := range columns {
+			row = append(
+				row,
+				cell(left.Rows[pair.Left], left.column(column)),
+				cell(right.Rows[pair.Right], right.column(column)),
+				strconv.FormatFloat(pair.Fields[idx].Similarity, 'f', 3, 64),
+			)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// cell returns the value of a column of a row, rows can be shorter than the header
+func cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	a.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func runLink(args ...string) (string, error) {
+	out := bytes.NewBuffer(nil)
+	err := link(args, nil, out)
+	return out.String(), err
+}
+
+func TestLink(t *testing.T) {
+	customers := writeFile(t, "customers.csv", "id,name,city\n1,Jonathan Smith,Amsterdam\n2,Maria Garcia,Rotterdam\n3,Pieter de Vries\n")
+	partner := writeFile(t, "partner.csv", "name,city,phone\n\"Garcia, Maria\",Roterdam,123\nJonathon Smith,Amsterdam,456\nJane Smith,Amsterdam,789\nKees Jansen,Rotterdam,000\n")
+
+	out, err := runLink(customers, partner)
+	a.NoError(t, err)
+	a.Equal(t, strings.Join([]string{
+		"left_row,right_row,score,left_name,right_name,name_similarity,left_city,right_city,city_similarity",
+		"1,2,1.000,Jonathan Smith,Jonathon Smith,1.000,Amsterdam,Amsterdam,1.000",
+		"1,3,0.750,Jonathan Smith,Jane Smith,0.500,Amsterdam,Amsterdam,1.000",
+		"2,1,1.000,Maria Garcia,\"Garcia, Maria\",1.000,Rotterdam,Roterdam,1.000",
+	}, "\n")+"\n", out)
+
+	out, err = runLink("-fields", "name", "-max", "1", customers, partner)
+	a.NoError(t, err)
+	a.Equal(t, strings.Join([]string{
+		"left_row,right_row,score,left_name,right_name,name_similarity",
+		"1,2,1.000,Jonathan Smith,Jonathon Smith,1.000",
+		"2,1,1.000,Maria Garcia,\"Garcia, Maria\",1.000",
+	}, "\n")+"\n", out)
+
+	out, err = runLink("-threshold", "0.5", customers, partner)
+	a.NoError(t, err)
+	a.Contains(t, out, "2,4,0.500,Maria Garcia,Kees Jansen,0.000,Rotterdam,Rotterdam,1.000\n", "only the city matches")
+	out, err = runLink("-threshold", "0.5", "-required", "name", customers, partner)
+	a.NoError(t, err)
+	a.NotContains(t, out, "Kees Jansen", "the name is required")
+}
+
+func TestLinkErrors(t *testing.T) {
+	customers := writeFile(t, "customers.csv", "name,city\nMaria Garcia,Rotterdam\n")
+	phones := writeFile(t, "phones.csv", "phone\n123\n")
+
+	for _, args := range [][]string{
+		{customers},
+		{customers, phones},
+		{"-fields", "phone", customers, customers},
+		{"-threshold", "0", customers, customers},
+		{"-fields", "name", "-required", "city", customers, customers},
+		{"-required", "phone", customers, customers},
+		{"-engine", "fast", customers, customers},
+		{customers, writeFile(t, "empty.csv", "")},
+		{customers, filepath.Join(t.TempDir(), "missing.csv")},
+	} {
+		_, err := runLink(args...)
+		a.Error(t, err, args)
+	}
+}
@@ -0,0 +1,96 @@
+// Command fuzzymatch contains tools to work with datasets of sentences
+//
+//	fuzzymatch dedup [-threshold 0.75] [-clusters] [-engine paths] [file]
+//	fuzzymatch link [-threshold 0.6] [-max 0] [-fields name,city] [-required name] [-engine paths] left.csv right.csv
+//
+// dedup reads one item per line from the file or stdin and prints the deduplicated items,
+// items that are near duplicates of each other are clustered using fuzzymatcher.ClusterItems and only the representative of every cluster is printed
+// With -clusters every cluster is printed with the representative on the first line followed by the other members indented with a tab,
+// clusters are separated by a empty line
+//
+// link finds the rows of two csv files that probably describe the same entity using fuzzymatcher.Link,
+// the first row of both files must contain the column names and columns with the same name are compared
+// It writes a csv file with a row per pair containing the row numbers, the score and per column both values and their similarity
+// Columns passed to -required must also be compared
+//
+// Both commands accept -engine to select the engine used to compare words, paths (the default) or trie
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+// commands contains all subcommands
+var commands = map[string]func(args []string, stdin io.Reader, stdout io.Writer) error{
+	"dedup": dedup,
+	"link":  link,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: fuzzymatch dedup [-threshold 0.75] [-clusters] [-engine paths] [file]")
+	fmt.Fprintln(os.Stderr, "       fuzzymatch link [-threshold 0.6] [-max 0] [-fields name,city] [-required name] [-engine paths] left.csv right.csv")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	err := command(os.Args[2:], os.Stdin, out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// openInput returns the file at the first argument or stdin if there are no arguments
+func openInput(args []string, stdin io.Reader) (io.ReadCloser, error) {
+	switch len(args) {
+	case 0:
+		return io.NopCloser(stdin), nil
+	case 1:
+		return os.Open(args[0])
+	default:
+		return nil, fmt.Errorf("expected at most one file but got %d", len(args))
+	}
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// engines contains the values of the -engine flag
+var engines = map[string]fuzzymatcher.Engine{
+	"paths": fuzzymatcher.EnginePaths,
+	"trie":  fuzzymatcher.EngineTrie,
+}
+
+func parseEngine(name string) (fuzzymatcher.Engine, error) {
+	engine, ok := engines[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown engine %q, expected paths or trie", name)
+	}
+	return engine, nil
+}
@@ -0,0 +1,105 @@
+// Command fuzzypick is a interactive picker that reads lines from stdin and prints the selected line to stdout
+//
+//	selected=$(git branch --format='%(refname:short)' | fuzzypick)
+//
+// The query is matched using (*fuzzymatcher.Matcher).Complete so the last word of the query is matched as a prefix
+// and typos are allowed, the matched words are highlighted
+//
+// Keys: up and down or ctrl+p and ctrl+n move the selection, enter prints the selected line,
+// ctrl+u clears the query and escape or ctrl+c exit without a selection
+//
+// With -filter the lines matching the query are printed in order of relevance without showing the picker,
+// this is useful in scripts. Exits with 1 if nothing was selected and 2 on errors
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	query := flag.String("query", "", "the initial query")
+	filter := flag.String("filter", "", "print the lines matching this query instead of showing the picker")
+	flag.Parse()
+
+	lines, err := readLines(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *filter != "" {
+		p := newPicker(lines)
+		p.Query = []rune(*filter)
+		p.update()
+		if len(p.Results) == 0 {
+			os.Exit(1)
+		}
+		writeResults(os.Stdout, p)
+		return
+	}
+
+	selected, ok, err := pick(lines, *query)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+	fmt.Println(selected)
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func writeResults(w io.Writer, p *picker) {
+	out := bufio.NewWriter(w)
+	for _, result := range p.Results {
+		fmt.Fprintln(out, p.Lines[result.Line])
+	}
+	out.Flush()
+}
+
+// pick shows the picker and returns the selected line
+func pick(lines []string, query string) (string, bool, error) {
+	t, err := openTerminal()
+	if err != nil {
+		return "", false, err
+	}
+	defer t.Close()
+
+	p := newPicker(lines)
+	p.Query = []rune(query)
+	p.update()
+
+	buf := make([]byte, 256)
+	for {
+		rows, cols := t.size()
+		p.render(t.tty, rows, cols)
+
+		n, err := t.tty.Read(buf)
+		if err != nil {
+			return "", false, err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			if p.handleKey(k) {
+				if k.Kind == keyCancel {
+					return "", false, nil
+				}
+				selected, ok := p.selection()
+				return selected, ok, nil
+			}
+		}
+	}
+}
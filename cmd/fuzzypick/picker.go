@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	fuzzymatcher "github.com/mjarkk/fuzzy-matcher"
+)
+
+const (
+	styleReset     = "\x1b[0m"
+	styleHighlight = "\x1b[1;32m"
+	styleSelected  = "\x1b[7m"
+)
+
+// result is a line that matches the query
+type result struct {
+	Line int
+	// Matched contains the indexes of the words of the line that are matched by the query
+	Matched []int
+}
+
+type picker struct {
+	Lines   []string
+	Matcher *fuzzymatcher.Matcher
+
+	Query    []rune
+	Results  []result
+	Selected int
+}
+
+func newPicker(lines []string) *picker {
+	p := &picker{
+		Lines:   lines,
+		Matcher: fuzzymatcher.NewMatcher(lines...),
+	}
+	p.update()
+	return p
+}
+
+// update ranks the lines using the query
+// Without a query all lines are shown in their original order
+func (p *picker) update() {
+	p.Selected = 0
+	p.Results = p.Results[:0]
+
+	// A query ending with a space does not complete anything so the last word is completed instead
+	query := strings.TrimRight(string(p.Query), " ")
+	if query == "" {
+		for idx := range p.Lines {
+			p.Results = append(p.Results, result{Line: idx})
+		}
+		return
+	}
+
+	for _, completion := range p.Matcher.Complete(query, 0) {
+		p.Results = append(p.Results, result{Line: completion.Sentence, Matched: completion.Matched})
+	}
+}
+
+// selection returns the selected line
+func (p *picker) selection() (string, bool) {
+	if p.Selected >= len(p.Results) {
+		return "", false
+	}
+	return p.Lines[p.Results[p.Selected].Line], true
+}
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyBackspace
+	keyClear
+	keyUp
+	keyDown
+	keyEnter
+	keyCancel
+	keyIgnored
+)
+
+type key struct {
+	Kind keyKind
+	Rune rune
+}
+
+// parseKeys parses the bytes read from the terminal in raw mode
+func parseKeys(data []byte) []key {
+	res := []key{}
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+
+		switch r {
+		case '\r', '\n':
+			res = append(res, key{Kind: keyEnter})
+		case 127, 8: // Backspace and ctrl+h
+			res = append(res, key{Kind: keyBackspace})
+		case 21: // ctrl+u
+			res = append(res, key{Kind: keyClear})
+		case 16: // ctrl+p
+			res = append(res, key{Kind: keyUp})
+		case 14: // ctrl+n
+			res = append(res, key{Kind: keyDown})
+		case 3, 4: // ctrl+c and ctrl+d
+			res = append(res, key{Kind: keyCancel})
+		case 27:
+			if len(data) >= 2 && (data[0] == '[' || data[0] == 'O') {
+				switch data[1] {
+				case 'A':
+					res = append(res, key{Kind: keyUp})
+				case 'B':
+					res = append(res, key{Kind: keyDown})
+				default:
+					res = append(res, key{Kind: keyIgnored})
+				}
+				data = data[2:]
+			} else {
+				// A escape that is not followed by a sequence is the escape key itself
+				res = append(res, key{Kind: keyCancel})
+			}
+		default:
+			if r >= ' ' && r != utf8.RuneError {
+				res = append(res, key{Kind: keyRune, Rune: r})
+			} else {
+				res = append(res, key{Kind: keyIgnored})
+			}
+		}
+	}
+	return res
+}
+
+// handleKey updates the picker for a key and returns true if the picker is done
+func (p *picker) handleKey(k key) bool {
+	switch k.Kind {
+	case keyRune:
+		p.Query = append(p.Query, k.Rune)
+		p.update()
+	case keyBackspace:
+		if len(p.Query) > 0 {
+			p.Query = p.Query[:len(p.Query)-1]
+			p.update()
+		}
+	case keyClear:
+		p.Query = p.Query[:0]
+		p.update()
+	case keyUp:
+		if p.Selected > 0 {
+			p.Selected--
+		}
+	case keyDown:
+		if p.Selected < len(p.Results)-1 {
+			p.Selected++
+		}
+	case keyEnter, keyCancel:
+		return true
+	}
+	return false
+}
+
+// highlight returns the line with the matched words highlighted, the line is cut off after width characters
+func highlight(line string, matched []int, width int) string {
+	spans := fuzzymatcher.WordSpans(line)
+	highlighted := make([]bool, len(line))
+	for _, wordIdx := range matched {
+		if wordIdx < len(spans) {
+			for i := spans[wordIdx].Start; i < spans[wordIdx].End; i++ {
+				highlighted[i] = true
+			}
+		}
+	}
+
+	res := strings.Builder{}
+	inHighlight := false
+	chars := 0
+	for i, c := range line {
+		if chars == width {
+			break
+		}
+		chars++
+		if c < ' ' {
+			// Control characters would mess up the terminal
+			c = ' '
+		}
+
+		if highlighted[i] != inHighlight {
+			inHighlight = highlighted[i]
+			if inHighlight {
+				res.WriteString(styleHighlight)
+			} else {
+				res.WriteString(styleReset)
+			}
+		}
+		res.WriteRune(c)
+	}
+	if inHighlight {
+		res.WriteString(styleReset)
+	}
+	return res.String()
+}
+
+// render draws the picker, the query is on the first line followed by as many results as fit
+func (p *picker) render(w io.Writer, rows, cols int) {
+	out := strings.Builder{}
+	// Move to the top left and clear the screen
+	out.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&out, "> %s\r\n", string(p.Query))
+
+	// Keep the selected line visible
+	visible := rows - 2
+	first := 0
+	if p.Selected >= visible {
+		first = p.Selected - visible + 1
+	}
+	for idx := first; idx < len(p.Results) && idx < first+visible; idx++ {
+		result := p.Results[idx]
+		if idx == p.Selected {
+			out.WriteString(styleSelected + "> " + styleReset)
+		} else {
+			out.WriteString("  ")
+		}
+		out.WriteString(highlight(p.Lines[result.Line], result.Matched, cols-2))
+		out.WriteString("\r\n")
+	}
+	fmt.Fprintf(&out, "  %d/%d", len(p.Results), len(p.Lines))
+
+	// Put the cursor after the query
+	fmt.Fprintf(&out, "\x1b[1;%dH", 3+len(p.Query))
+	io.WriteString(w, out.String())
+}
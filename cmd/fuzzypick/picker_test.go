@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+var testLines = []string{
+	"feature/fuzzy-search",
+	"main",
+	"fix/search-crash",
+	"feature/reload",
+}
+
+func resultLines(p *picker) []string {
+	res := []string{}
+	for _, result := range p.Results {
+		res = append(res, p.Lines[result.Line])
+	}
+	return res
+}
+
+func typeQuery(p *picker, query string) {
+	for _, k := range parseKeys([]byte(query)) {
+		p.handleKey(k)
+	}
+}
+
+func TestPickerUpdate(t *testing.T) {
+	p := newPicker(testLines)
+	a.Equal(t, testLines, resultLines(p), "without a query all lines are shown")
+
+	typeQuery(p, "sea")
+	a.Equal(t, []string{"feature/fuzzy-search", "fix/search-crash"}, resultLines(p))
+
+	// Lines that match more words of the query are ranked higher
+	typeQuery(p, "rch fea")
+	a.Equal(t, []string{"feature/fuzzy-search", "feature/reload"}, resultLines(p))
+	a.Equal(t, []int{0, 2}, p.Results[0].Matched)
+
+	typeQuery(p, " ")
+	a.Equal(t, []string{"feature/fuzzy-search", "feature/reload"}, resultLines(p), "a trailing space keeps the results")
+
+	typeQuery(p, "\x15")
+	a.Len(t, p.Query, 0)
+	a.Equal(t, testLines, resultLines(p))
+
+	typeQuery(p, "relod")
+	a.Equal(t, []string{"feature/reload"}, resultLines(p), "typos are allowed")
+	typeQuery(p, "\x7f\x7f\x7f\x7f\x7f")
+	a.Len(t, p.Results, len(testLines))
+}
+
+func TestPickerSelection(t *testing.T) {
+	p := newPicker(testLines)
+	selected, ok := p.selection()
+	a.True(t, ok)
+	a.Equal(t, "feature/fuzzy-search", selected)
+
+	typeQuery(p, "\x1b[B\x1b[B\x0e\x0e\x0e")
+	a.Equal(t, 3, p.Selected, "the selection stops at the last result")
+	typeQuery(p, "\x1b[A\x10")
+	selected, _ = p.selection()
+	a.Equal(t, "main", selected)
+
+	typeQuery(p, "re")
+	a.Equal(t, 0, p.Selected, "typing resets the selection")
+
+	typeQuery(p, "zzzzz")
+	_, ok = p.selection()
+	a.False(t, ok)
+
+	a.True(t, p.handleKey(key{Kind: keyEnter}))
+	a.True(t, p.handleKey(key{Kind: keyCancel}))
+	a.False(t, p.handleKey(key{Kind: keyRune, Rune: 'a'}))
+}
+
+func TestParseKeys(t *testing.T) {
+	a.Equal(t, []key{
+		{Kind: keyRune, Rune: 'a'},
+		{Kind: keyRune, Rune: 'ö'},
+		{Kind: keyUp},
+		{Kind: keyDown},
+		{Kind: keyIgnored},
+		{Kind: keyBackspace},
+		{Kind: keyEnter},
+		{Kind: keyCancel},
+		{Kind: keyCancel},
+	}, parseKeys([]byte("aö\x1b[A\x1bOB\x1b[C\x7f\r\x03\x1b")))
+}
+
+func TestHighlight(t *testing.T) {
+	a.Equal(t, "feature/"+styleHighlight+"fuzzy"+styleReset+"-"+styleHighlight+"search"+styleReset, highlight("feature/fuzzy-search", []int{1, 2}, 100))
+	a.Equal(t, "feature/"+styleHighlight+"fu"+styleReset, highlight("feature/fuzzy-search", []int{1, 2}, 10))
+	a.Equal(t, "tab sep", highlight("tab\tsep", nil, 100))
+	a.Equal(t, "bär", highlight("bär", nil, 3))
+}
+
+func TestRender(t *testing.T) {
+	p := newPicker(testLines)
+	typeQuery(p, "feat")
+
+	out := bytes.NewBuffer(nil)
+	p.render(out, 3, 80)
+	a.Contains(t, out.String(), "> feat\r\n")
+	a.Contains(t, out.String(), styleHighlight+"feature"+styleReset+"/reload")
+	a.NotContains(t, out.String(), "fuzzy-search", "only one result fits")
+	a.Contains(t, out.String(), "2/4")
+
+	typeQuery(p, "\x1b[B")
+	out.Reset()
+	p.render(out, 3, 80)
+	a.Contains(t, out.String(), "/fuzzy-search", "the selected line is always visible")
+}
+
+func TestFilter(t *testing.T) {
+	lines, err := readLines(strings.NewReader(strings.Join(testLines, "\n") + "\n"))
+	a.NoError(t, err)
+	a.Equal(t, testLines, lines)
+
+	p := newPicker(lines)
+	typeQuery(p, "search")
+	out := bytes.NewBuffer(nil)
+	writeResults(out, p)
+	a.Equal(t, "feature/fuzzy-search\nfix/search-crash\n", out.String())
+}
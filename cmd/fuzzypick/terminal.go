@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// terminal is the controlling terminal, it's used instead of stdin and stdout as those are used for the lines and the selection
+type terminal struct {
+	tty *os.File
+	// state is the stty state before switching to raw mode
+	state string
+}
+
+// openTerminal opens the controlling terminal and switches it to raw mode
+// stty is used so no platform specific system calls are needed
+func openTerminal() (*terminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the terminal: %w", err)
+	}
+	t := &terminal{tty: tty}
+
+	t.state, err = t.stty("-g")
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+	_, err = t.stty("raw", "-echo")
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+
+	// Use the alternate screen so the terminal is restored when done
+	fmt.Fprint(tty, "\x1b[?1049h")
+	return t, nil
+}
+
+func (t *terminal) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = t.tty
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// size returns the amount of rows and columns of the terminal
+func (t *terminal) size() (rows int, cols int) {
+	out, err := t.stty("size")
+	if err == nil {
+		_, err = fmt.Sscan(out, &rows, &cols)
+	}
+	if err != nil || rows < 3 || cols < 10 {
+		return 24, 80
+	}
+	return rows, cols
+}
+
+// Close restores the terminal
+func (t *terminal) Close() error {
+	fmt.Fprint(t.tty, "\x1b[?1049l")
+	_, err := t.stty(t.state)
+	t.tty.Close()
+	return err
+}
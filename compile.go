@@ -0,0 +1,187 @@
+package fuzzymatcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxWords is the maximum amount of words a sentence can have
+// Words after this are ignored by NewMatcher
+const MaxWords = 64
+
+// DefaultMinWordLength is used by Validate and Compile if CompileOptions.MinWordLength is 0
+const DefaultMinWordLength = 2
+
+// DiagnosticKind describes the kind of problem found in a sentence
+type DiagnosticKind string
+
+const (
+	// DiagnosticEmpty means the sentence has no words after normalization so it can never match
+	DiagnosticEmpty DiagnosticKind = "empty"
+	// DiagnosticTooManyWords means the sentence has more than MaxWords words
+	DiagnosticTooManyWords DiagnosticKind = "too-many-words"
+	// DiagnosticDuplicate means the sentence has the same words as an earlier sentence so both always match the same inputs,
+	// Match only returns the later sentence if the tie break policy prefers it, for example because of a higher priority with TieBreakPriority
+	DiagnosticDuplicate DiagnosticKind = "duplicate"
+	// DiagnosticShortWord means the sentence contains a word shorter than CompileOptions.MinWordLength
+	DiagnosticShortWord DiagnosticKind = "short-word"
+	// DiagnosticIgnoredChars means the sentence contains characters that are ignored while matching
+	DiagnosticIgnoredChars DiagnosticKind = "ignored-chars"
+)
+
+// Diagnostic describes a problem found in one of the sentences
+type Diagnostic struct {
+	// Sentence is the index of the sentence within the input
+	Sentence int
+	Kind     DiagnosticKind
+
+	// Word is the word this diagnostic is about, only set for DiagnosticShortWord
+	Word string
+	// Chars contains the ignored characters, only set for DiagnosticIgnoredChars
+	Chars []rune
+	// DuplicateOf is the index of the earlier sentence, only set for DiagnosticDuplicate
+	DuplicateOf int
+	// Words is the amount of words, only set for DiagnosticTooManyWords
+	Words int
+}
+
+// IsWarning returns true if the sentence can still be used
+// Warnings only make Compile fail if CompileOptions.Strict is set
+func (d Diagnostic) IsWarning() bool {
+	return d.Kind == DiagnosticShortWord || d.Kind == DiagnosticIgnoredChars
+}
+
+func (d Diagnostic) String() string {
+	switch d.Kind {
+	case DiagnosticEmpty:
+		return fmt.Sprintf("sentence %d: no words left after normalization", d.Sentence)
+	case DiagnosticTooManyWords:
+		return fmt.Sprintf("sentence %d: has %d words but the maximum is %d", d.Sentence, d.Words, MaxWords)
+	case DiagnosticDuplicate:
+		return fmt.Sprintf("sentence %d: duplicate of sentence %d", d.Sentence, d.DuplicateOf)
+	case DiagnosticShortWord:
+		return fmt.Sprintf("sentence %d: word %q is too short to be matched fuzzy", d.Sentence, d.Word)
+	case DiagnosticIgnoredChars:
+		return fmt.Sprintf("sentence %d: characters %q are ignored", d.Sentence, string(d.Chars))
+	default:
+		return fmt.Sprintf("sentence %d: %s", d.Sentence, d.Kind)
+	}
+}
+
+// CompileError is returned by Compile if there are problems with the sentences
+type CompileError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *CompileError) Error() string {
+	if len(e.Diagnostics) == 1 {
+		return e.Diagnostics[0].String()
+	}
+	return fmt.Sprintf("%s (and %d more problems)", e.Diagnostics[0].String(), len(e.Diagnostics)-1)
+}
+
+// CompileOptions changes the behavior of Compile
+type CompileOptions struct {
+	// MinWordLength reports words with less letters than this as DiagnosticShortWord
+	// 0 uses DefaultMinWordLength and a negative value disables this check
+	MinWordLength int
+	// Strict makes Compile also fail on warnings
+	Strict bool
+	// Limits, TieBreak, Priorities, Engine and Prefilter are set on the created matcher
+	Limits     Limits
+	TieBreak   TieBreak
+	Priorities []int
+	Engine     Engine
+	Prefilter  bool
+}
+
+// Validate checks the sentences for problems that NewMatcher would silently ignore
+// The diagnostics are ordered by sentence
+func Validate(sentences []string, opts CompileOptions) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	sentenceIdxByWords := map[string]int{}
+	minWordLength := opts.MinWordLength
+	if minWordLength == 0 {
+		minWordLength = DefaultMinWordLength
+	}
+
+	for sentenceIdx, sentence := range sentences {
+		parsedWords, ignoredChars := parseSentence(sentence)
+
+		if len(parsedWords) == 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Sentence: sentenceIdx,
+				Kind:     DiagnosticEmpty,
+			})
+			continue
+		}
+
+		if len(parsedWords) > MaxWords {
+			diagnostics = append(diagnostics, Diagnostic{
+				Sentence: sentenceIdx,
+				Kind:     DiagnosticTooManyWords,
+				Words:    len(parsedWords),
+			})
+		}
+
+		words := make([]string, len(parsedWords))
+		for idx, word := range parsedWords {
+			words[idx] = string(word)
+		}
+		key := strings.Join(words, " ")
+		duplicateOf, ok := sentenceIdxByWords[key]
+		if ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Sentence:    sentenceIdx,
+				Kind:        DiagnosticDuplicate,
+				DuplicateOf: duplicateOf,
+			})
+		} else {
+			sentenceIdxByWords[key] = sentenceIdx
+		}
+
+		if minWordLength > 0 {
+			for _, word := range parsedWords {
+				if len(word) < minWordLength {
+					diagnostics = append(diagnostics, Diagnostic{
+						Sentence: sentenceIdx,
+						Kind:     DiagnosticShortWord,
+						Word:     string(word),
+					})
+				}
+			}
+		}
+
+		if len(ignoredChars) > 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Sentence: sentenceIdx,
+				Kind:     DiagnosticIgnoredChars,
+				Chars:    ignoredChars,
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// Compile is the same as NewMatcher but returns a *CompileError if problems are found in the sentences
+// See Validate for the checks that are done
+func Compile(sentences []string, opts CompileOptions) (*Matcher, error) {
+	problems := []Diagnostic{}
+	for _, diagnostic := range Validate(sentences, opts) {
+		if opts.Strict || !diagnostic.IsWarning() {
+			problems = append(problems, diagnostic)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &CompileError{Diagnostics: problems}
+	}
+
+	m := NewMatcher(sentences...)
+	m.Limits = opts.Limits
+	m.TieBreak = opts.TieBreak
+	m.Priorities = opts.Priorities
+	m.Engine = opts.Engine
+	m.Prefilter = opts.Prefilter
+	return m, nil
+}
@@ -0,0 +1,67 @@
+package fuzzymatcher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	diagnostics := Validate([]string{
+		"I love trees",
+		"  ,.!? ",
+		"i LOVE trees!",
+		strings.Repeat("word ", MaxWords+1),
+		"a banana",
+		"“banana”",
+	}, CompileOptions{})
+
+	a.Equal(t, []Diagnostic{
+		{Sentence: 0, Kind: DiagnosticShortWord, Word: "i"},
+		{Sentence: 1, Kind: DiagnosticEmpty},
+		{Sentence: 2, Kind: DiagnosticDuplicate, DuplicateOf: 0},
+		{Sentence: 2, Kind: DiagnosticShortWord, Word: "i"},
+		{Sentence: 3, Kind: DiagnosticTooManyWords, Words: MaxWords + 1},
+		{Sentence: 4, Kind: DiagnosticShortWord, Word: "a"},
+		{Sentence: 5, Kind: DiagnosticIgnoredChars, Chars: []rune{'“', '”'}},
+	}, diagnostics)
+
+	a.Equal(t, []Diagnostic{}, Validate([]string{"I love trees", "banana"}, CompileOptions{MinWordLength: -1}))
+	a.Equal(t, []Diagnostic{
+		{Sentence: 0, Kind: DiagnosticShortWord, Word: "i"},
+		{Sentence: 0, Kind: DiagnosticShortWord, Word: "love"},
+	}, Validate([]string{"I love trees", "banana"}, CompileOptions{MinWordLength: 5}))
+}
+
+func TestCompile(t *testing.T) {
+	m, err := Compile([]string{"I love trees", "“banana”"}, CompileOptions{
+		Limits: Limits{MaxInputBytes: 100},
+	})
+	a.NoError(t, err)
+	a.Equal(t, 1, m.Match("bananas"))
+	a.Equal(t, 100, m.Limits.MaxInputBytes)
+
+	_, err = Compile([]string{"I love trees", "“banana”"}, CompileOptions{Strict: true})
+	a.Error(t, err)
+
+	_, err = Compile([]string{"banana", "", "Banana"}, CompileOptions{})
+	var compileErr *CompileError
+	a.True(t, errors.As(err, &compileErr))
+	a.Len(t, compileErr.Diagnostics, 2)
+	a.Equal(t, DiagnosticEmpty, compileErr.Diagnostics[0].Kind)
+	a.Equal(t, DiagnosticDuplicate, compileErr.Diagnostics[1].Kind)
+	a.Equal(t, "sentence 1: no words left after normalization (and 1 more problems)", err.Error())
+}
+
+func TestDuplicateWithPriority(t *testing.T) {
+	sentences := []string{"banana", "Banana"}
+	a.Equal(t, DiagnosticDuplicate, Validate(sentences, CompileOptions{})[0].Kind)
+
+	m := NewMatcher(sentences...)
+	a.Equal(t, 0, m.Match("banana"))
+	m.TieBreak = TieBreakPriority
+	m.Priorities = []int{0, 1}
+	a.Equal(t, 1, m.Match("banana"), "the duplicate is returned if it has a higher priority")
+}
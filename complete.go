@@ -0,0 +1,109 @@
+package fuzzymatcher
+
+import (
+	"sort"
+)
+
+// Completion is a sentence that can be completed from an incomplete input
+type Completion struct {
+	// Sentence is the index of the sentence within the NewMatcher input
+	Sentence int
+	// Word is the index of the sentence word that is completed by the last input word
+	Word int
+	// MatchedWords is the amount of sentence words matched by the input including the completed word
+	MatchedWords int
+	// Matched contains the indexes of the sentence words matched by the input including the completed word, use WordSpans to find them in the sentence
+	Matched []int
+	// Complete is true if all words of the sentence are matched
+	Complete bool
+	// Score is 1 if all matched letters are exact and gets lower the more letters had to be skipped
+	// Letters that are not yet typed do not lower the score
+	Score float64
+}
+
+// Complete is used for autocompletion, the last word of the input is matched as a prefix of the sentence words
+// The typed letters of the last word are still matched fuzzy so "bnan" completes to "banana"
+// A last word with only a few letters has to start at the first letter of the sentence word so "a" does not complete to "banana"
+// Only sentences with a word that is completed by the last input word are returned, if the input ends with a space nothing is returned
+// The completions are ranked by: most matched words, highest score, least words and finally the order of the NewMatcher input
+// n limits the amount of returned completions, if n is 0 or lower all completions are returned
+func (m *Matcher) Complete(input string, n int) []Completion {
+	state := m.getState()
+	defer m.putState(state)
+	state.prefix = true
+
+	// completedWordBySentence contains the best matching word of the last input word for every sentence
+	type completedWord struct {
+		Word         int
+		WordIdx      uint64
+		SkippedChars int
+	}
+	completedWordBySentence := map[int]completedWord{}
+
+	state.walk(input, false, func(entry *inProgressMatch, p posting, start, end int) bool {
+		if !state.prefixWord {
+			state.addWordIdxToSentence(entry, p, start, end)
+			return true
+		}
+
+		current, ok := completedWordBySentence[int(p.Sentence)]
+		if !ok || entry.SkippedChars < current.SkippedChars || (entry.SkippedChars == current.SkippedChars && int(p.Word) < current.Word) {
+			completedWordBySentence[int(p.Sentence)] = completedWord{
+				Word:         int(p.Word),
+				WordIdx:      p.wordIdx(),
+				SkippedChars: entry.SkippedChars,
+			}
+		}
+		return true
+	})
+
+	res := []Completion{}
+	for sentenceIdx, completed := range completedWordBySentence {
+		sentence := &m.Sentences[sentenceIdx]
+		progress := state.Sentences[sentenceIdx]
+
+		indexSum := progress.IndexSum | completed.WordIdx
+		letters := int(sentence.SentenceLen) - sentence.wordCount() + 1
+		score := 1 - float64(progress.SkippedChars+completed.SkippedChars)/float64(letters)
+		if score < 0 {
+			score = 0
+		}
+
+		matched := bitIndexes(indexSum)
+
+		res = append(res, Completion{
+			Sentence:     int(sentence.IdxInNewMatcherInput),
+			Word:         completed.Word,
+			MatchedWords: len(matched),
+			Matched:      matched,
+			Complete:     indexSum == sentence.indexSum(),
+			Score:        score,
+		})
+	}
+
+	wordsBySentence := map[int]int{}
+	for _, sentence := range m.Sentences {
+		wordsBySentence[int(sentence.IdxInNewMatcherInput)] = sentence.wordCount()
+	}
+	sort.Slice(res, func(a, b int) bool {
+		completionA := res[a]
+		completionB := res[b]
+		if completionA.MatchedWords != completionB.MatchedWords {
+			return completionA.MatchedWords > completionB.MatchedWords
+		}
+		if completionA.Score != completionB.Score {
+			return completionA.Score > completionB.Score
+		}
+		wordsA := wordsBySentence[completionA.Sentence]
+		wordsB := wordsBySentence[completionB.Sentence]
+		if wordsA != wordsB {
+			return wordsA < wordsB
+		}
+		return completionA.Sentence < completionB.Sentence
+	})
+
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
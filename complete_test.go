@@ -0,0 +1,69 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	m := NewMatcher(
+		"banana shake",
+		"banana bread",
+		"bandana",
+		"I love trees",
+		"apple",
+	)
+
+	a.Equal(t, []int{0, 1, 2}, completionSentences(m.Complete("bana", 0)))
+	a.Equal(t, []int{0, 1}, completionSentences(m.Complete("bana", 2)))
+
+	completions := m.Complete("i love tr", 0)
+	a.Equal(t, []Completion{{Sentence: 3, Word: 2, MatchedWords: 3, Matched: []int{0, 1, 2}, Complete: true, Score: 1}}, completions)
+
+	completions = m.Complete("banana sh", 0)
+	a.Equal(t, []Completion{{Sentence: 0, Word: 1, MatchedWords: 2, Matched: []int{0, 1}, Complete: true, Score: 1}}, completions)
+
+	// The typed letters are still matched fuzzy
+	completions = m.Complete("bnan", 0)
+	a.Equal(t, []int{0, 1}, completionSentences(completions))
+	a.Less(t, completions[0].Score, 1.0)
+
+	// The last word is finished so there is nothing to complete
+	a.Len(t, m.Complete("banana ", 0), 0)
+	a.Len(t, m.Complete("", 0), 0)
+	a.Len(t, m.Complete("xyz", 0), 0)
+
+	// Complete does not change the behavior of Match
+	a.Equal(t, -1, m.Match("bana"))
+}
+
+func TestCompleteShortPrefix(t *testing.T) {
+	m := NewMatcher(
+		"banana",
+		"apple",
+		"tree",
+		"coordinator",
+	)
+
+	for _, engine := range []Engine{EnginePaths, EngineTrie} {
+		m.Engine = engine
+
+		// A prefix that is not longer than the allowed offset has to start at the first letter of the word
+		a.Equal(t, []int{1}, completionSentences(m.Complete("a", 0)), "engine %d", engine)
+		a.Equal(t, []int{0}, completionSentences(m.Complete("b", 0)), "engine %d", engine)
+		a.Len(t, m.Complete("oo", 0), 0, "engine %d", engine)
+
+		// Longer prefixes can still skip the first letters
+		a.Equal(t, []int{0}, completionSentences(m.Complete("ana", 0)), "engine %d", engine)
+		a.Equal(t, []int{3}, completionSentences(m.Complete("oordi", 0)), "engine %d", engine)
+	}
+}
+
+func completionSentences(completions []Completion) []int {
+	res := []int{}
+	for _, completion := range completions {
+		res = append(res, completion.Sentence)
+	}
+	return res
+}
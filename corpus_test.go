@@ -0,0 +1,248 @@
+package fuzzymatcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+var updateCorpora = flag.Bool("update-corpora", false, "rewrite the benchmark corpora in testdata/bench")
+
+const (
+	// benchCorpusSize is the amount of sentences stored per corpus, larger sizes are derived from it by loadBenchCorpus
+	benchCorpusSize = 100_000
+	// benchQueries is the amount of hits and misses stored per corpus
+	benchQueries = 1_000
+	// benchHitSentences is the amount of sentences at the start of a corpus the hits are made of so they match every corpus size
+	benchHitSentences = 100
+)
+
+// benchCorpus describes how a benchmark corpus is generated
+type benchCorpus struct {
+	Name string
+	Seed int64
+	// Syllables are combined into the words of the sentences
+	Syllables []string
+	// MissSyllables do not share letters with Syllables so words made of them never match a word of the corpus,
+	// every syllable has at least 2 letters
+	MissSyllables []string
+}
+
+var benchCorpora = []benchCorpus{
+	{
+		Name:          "ascii",
+		Seed:          1,
+		Syllables:     []string{"ba", "ko", "ri", "ten", "lo", "mar", "vi", "su", "pe", "dan", "go", "xi", "ra", "mel", "to", "fin"},
+		MissSyllables: []string{"chy", "zyw", "jhq", "wych", "czy", "qwy"},
+	},
+	{
+		Name:          "unicode",
+		Seed:          2,
+		Syllables:     []string{"жа", "ли", "ми", "до", "σο", "φι", "κα", "λη", "日", "本", "語", "東", "grü", "ßen", "çe", "ño"},
+		MissSyllables: []string{"щю", "эя", "ψω", "ξυ", "漢", "字", "hyw", "zyk"},
+	},
+}
+
+// benchCorpusFiles contains the generated files of a corpus
+type benchCorpusFiles struct {
+	Sentences []string
+	Hits      []string
+	Misses    []string
+}
+
+func (c benchCorpus) path(suffix string) string {
+	return filepath.Join("testdata", "bench", c.Name+suffix)
+}
+
+// generate creates the sentences, hits and misses of the corpus
+// The output only depends on the seed so the checked in files can be verified
+func (c benchCorpus) generate() benchCorpusFiles {
+	r := rand.New(rand.NewSource(c.Seed))
+	word := func(syllables []string) string {
+		res := ""
+		for i := 2 + r.Intn(3); i > 0; i-- {
+			res += syllables[r.Intn(len(syllables))]
+		}
+		return res
+	}
+
+	words := make([]string, 30_000)
+	for idx := range words {
+		words[idx] = word(c.Syllables)
+	}
+
+	res := benchCorpusFiles{Sentences: make([]string, benchCorpusSize)}
+	for idx := range res.Sentences {
+		sentenceWords := make([]string, 2+r.Intn(4))
+		for wordIdx := range sentenceWords {
+			// Some words are a lot more common than others like in real sentences
+			sentenceWords[wordIdx] = words[r.Intn(r.Intn(len(words))+1)]
+		}
+		res.Sentences[idx] = strings.Join(sentenceWords, " ")
+	}
+
+	filler := func() []string {
+		res := make([]string, r.Intn(4))
+		for idx := range res {
+			res[idx] = word(c.MissSyllables)
+		}
+		return res
+	}
+
+	// A hit is one of the first sentences between words that do not match, some words of 5 or more letters miss one letter
+	// which is within the allowed typos of such words so the sentence always matches
+	for len(res.Hits) < benchQueries {
+		sentenceWords := strings.Split(res.Sentences[r.Intn(benchHitSentences)], " ")
+		for wordIdx, sentenceWord := range sentenceWords {
+			letters := []rune(sentenceWord)
+			if len(letters) >= 5 && r.Intn(2) == 0 {
+				removed := 1 + r.Intn(len(letters)-1)
+				sentenceWords[wordIdx] = string(letters[:removed]) + string(letters[removed+1:])
+			}
+		}
+		if first := sentenceWords[0][0]; first >= 'a' && first <= 'z' && r.Intn(2) == 0 {
+			sentenceWords[0] = strings.ToUpper(sentenceWords[0][:1]) + sentenceWords[0][1:]
+		}
+
+		res.Hits = append(res.Hits, strings.Join(append(append(filler(), sentenceWords...), filler()...), " "))
+	}
+
+	// A miss contains a word of the corpus so the matcher has to do some work, but followed by at least 4 letters that are not in the corpus
+	// As no word allows more than 2 typos it can't match any word, like the other words that only contain letters that are not in the corpus
+	for len(res.Misses) < benchQueries {
+		sentenceWords := strings.Split(res.Sentences[r.Intn(len(res.Sentences))], " ")
+		queryWords := append(filler(), word(c.MissSyllables))
+		queryWords = append(queryWords, sentenceWords[r.Intn(len(sentenceWords))]+word(c.MissSyllables))
+		queryWords = append(queryWords, filler()...)
+		res.Misses = append(res.Misses, strings.Join(queryWords, " "))
+	}
+
+	return res
+}
+
+func readBenchFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		r = gzipReader
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func writeBenchFile(path string, lines []string) error {
+	data := []byte(strings.Join(lines, "\n") + "\n")
+	if strings.HasSuffix(path, ".gz") {
+		buf := bytes.NewBuffer(nil)
+		w, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+		if err != nil {
+			return err
+		}
+		w.Write(data)
+		if err := w.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func TestBenchCorpora(t *testing.T) {
+	if (testing.Short() || raceEnabled) && !*updateCorpora {
+		t.Skip("generating the corpora takes a while")
+	}
+
+	for _, corpus := range benchCorpora {
+		generated := corpus.generate()
+		files := []struct {
+			suffix string
+			lines  []string
+		}{
+			{".txt.gz", generated.Sentences},
+			{"-hits.txt", generated.Hits},
+			{"-misses.txt", generated.Misses},
+		}
+
+		for _, file := range files {
+			path := corpus.path(file.suffix)
+			if *updateCorpora {
+				a.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				a.NoError(t, writeBenchFile(path, file.lines))
+				continue
+			}
+
+			lines, err := readBenchFile(path)
+			a.NoError(t, err)
+			if !a.Equal(t, len(file.lines), len(lines), path) {
+				continue
+			}
+			for idx := range lines {
+				if lines[idx] != file.lines[idx] {
+					t.Errorf("%s:%d is %q but the generator creates %q, run go test -run TestBenchCorpora -update-corpora", path, idx+1, lines[idx], file.lines[idx])
+					break
+				}
+			}
+		}
+	}
+}
+
+var (
+	benchCorporaLock   sync.Mutex
+	loadedBenchCorpora = map[string]benchCorpusFiles{}
+)
+
+// loadBenchCorpus returns the first size sentences of a corpus together with its hits and misses
+// Sizes larger than the stored corpus repeat it with a extra word per repetition so all sentences stay different
+func loadBenchCorpus(tb testing.TB, name string, size int) benchCorpusFiles {
+	benchCorporaLock.Lock()
+	defer benchCorporaLock.Unlock()
+
+	files, ok := loadedBenchCorpora[name]
+	if !ok {
+		corpus := benchCorpus{Name: name}
+		var err error
+		files.Sentences, err = readBenchFile(corpus.path(".txt.gz"))
+		a.NoError(tb, err)
+		files.Hits, err = readBenchFile(corpus.path("-hits.txt"))
+		a.NoError(tb, err)
+		files.Misses, err = readBenchFile(corpus.path("-misses.txt"))
+		a.NoError(tb, err)
+		loadedBenchCorpora[name] = files
+	}
+
+	stored := files.Sentences
+	sentences := make([]string, size)
+	for idx := range sentences {
+		sentences[idx] = stored[idx%len(stored)]
+		if repetition := idx / len(stored); repetition > 0 {
+			sentences[idx] += " " + strings.SplitN(stored[repetition], " ", 2)[0]
+		}
+	}
+	files.Sentences = sentences
+	return files
+}
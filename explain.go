@@ -0,0 +1,188 @@
+package fuzzymatcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StepKind describes what happened to a word of a sentence while matching an input letter
+type StepKind string
+
+const (
+	// StepStarted means the first letter of an input word matched one of the first letters of the sentence word
+	StepStarted StepKind = "started"
+	// StepAlreadyMatched means the sentence word matched but was already matched by an earlier input word
+	StepAlreadyMatched StepKind = "already-matched"
+	// StepAdvanced means the input letter matched one of the next letters of the sentence word
+	StepAdvanced StepKind = "advanced"
+	// StepSkipped means the input letter did not match so one of the allowed skipped letters was used
+	StepSkipped StepKind = "skipped"
+	// StepDropped means the input letter did not match and there are no skipped letters left
+	StepDropped StepKind = "dropped"
+	// StepMatched means the input word ended and the sentence word matched
+	StepMatched StepKind = "matched"
+	// StepIncomplete means the input word ended but to many letters of the sentence word where missing
+	StepIncomplete StepKind = "incomplete"
+)
+
+// Step is one step of matching a sentence word against an input word
+type Step struct {
+	Kind StepKind
+	// Word is the index of the sentence word
+	Word int
+	// Letter is the input letter of this step, 0 for StepMatched and StepIncomplete
+	Letter rune
+	// WordOffset is the index of the last matched letter of the sentence word after this step
+	WordOffset int
+	// SkippedChars is the amount of letters that where skipped after this step
+	SkippedChars int
+	// AllowedOffset is the maximum amount of letters that can be skipped for the sentence word
+	AllowedOffset int
+}
+
+// ExplainToken is a word of the input together with the steps taken for it
+type ExplainToken struct {
+	Text  string
+	Start int
+	End   int
+	Steps []Step
+}
+
+// Explanation describes why an input did or did not match a sentence
+type Explanation struct {
+	// Sentence is the index of the sentence within the NewMatcher input
+	Sentence int
+	// Words contains the normalized words of the sentence
+	Words  []string
+	Tokens []ExplainToken
+	// WordMatchedBy contains for every sentence word the index of the token that matched it or -1
+	WordMatchedBy []int
+	// Matched is true if all words of the sentence matched
+	Matched bool
+	// MatchResult is the result of Match for the input, this can be another sentence that completed earlier
+	MatchResult int
+}
+
+// Explain matches the input against one sentence and returns every step taken by the matcher
+// sentenceIdx is the index of the sentence within the NewMatcher input
+// Returns an error if the sentence does not exist or was ignored as it has no words
+// The steps are always those of EnginePaths, MatchResult uses the matchers Engine
+func (m *Matcher) Explain(input string, sentenceIdx int) (Explanation, error) {
+	target := -1
+	for idx, sentence := range m.Sentences {
+		if int(sentence.IdxInNewMatcherInput) == sentenceIdx {
+			target = idx
+			break
+		}
+	}
+	if target == -1 {
+		return Explanation{}, fmt.Errorf("sentence %d does not exist or has no words", sentenceIdx)
+	}
+	sentence := &m.Sentences[target]
+
+	res := Explanation{
+		Sentence:      sentenceIdx,
+		Words:         m.words(sentence),
+		Tokens:        tokenize(input),
+		WordMatchedBy: make([]int, sentence.wordCount()),
+		MatchResult:   m.Match(input),
+	}
+	// wordsByVocabularyIdx contains the sentence words for every vocabulary word of the sentence
+	wordsByVocabularyIdx := map[int32][]int{}
+	for idx, word := range m.sentenceWords(sentence) {
+		res.WordMatchedBy[idx] = -1
+		wordsByVocabularyIdx[word] = append(wordsByVocabularyIdx[word], idx)
+	}
+
+	tokenIdxByStart := map[int]int{}
+	for idx, token := range res.Tokens {
+		tokenIdxByStart[token.Start] = idx
+	}
+
+	state := m.getState()
+	defer m.putState(state)
+
+	addStep := func(kind StepKind, word int, entry *inProgressMatch, letter rune) {
+		tokenIdx := tokenIdxByStart[state.wordStart]
+		res.Tokens[tokenIdx].Steps = append(res.Tokens[tokenIdx].Steps, Step{
+			Kind:          kind,
+			Word:          word,
+			Letter:        letter,
+			WordOffset:    entry.WordOffset,
+			SkippedChars:  entry.SkippedChars,
+			AllowedOffset: entry.AllowedOffset,
+		})
+	}
+	state.trace = func(kind StepKind, entry *inProgressMatch, letter rune) {
+		for _, word := range wordsByVocabularyIdx[entry.PathToWord.Word] {
+			addStep(kind, word, entry, letter)
+		}
+	}
+	// All occurrences are walked so words that where already matched can be reported
+	state.walk(input, true, func(entry *inProgressMatch, p posting, start, end int) bool {
+		if int(p.Sentence) != target {
+			return true
+		}
+		if state.Sentences[p.Sentence].IndexSum&p.wordIdx() != 0 {
+			addStep(StepAlreadyMatched, int(p.Word), entry, 0)
+			return true
+		}
+		if res.WordMatchedBy[p.Word] == -1 {
+			res.WordMatchedBy[p.Word] = tokenIdxByStart[start]
+		}
+		if state.addWordIdxToSentence(entry, p, start, end) {
+			res.Matched = true
+		}
+		return true
+	})
+
+	return res, nil
+}
+
+// tokenize splits the input into words the same way the matcher does
+func tokenize(input string) []ExplainToken {
+	spans := WordSpans(input)
+	res := make([]ExplainToken, len(spans))
+	for idx, span := range spans {
+		res[idx] = ExplainToken{
+			Text:  input[span.Start:span.End],
+			Start: span.Start,
+			End:   span.End,
+			Steps: []Step{},
+		}
+	}
+	return res
+}
+
+func (e Explanation) String() string {
+	res := strings.Builder{}
+	fmt.Fprintf(&res, "sentence %d with words %q\n", e.Sentence, e.Words)
+
+	for _, token := range e.Tokens {
+		fmt.Fprintf(&res, "input word %q at %d-%d\n", token.Text, token.Start, token.End)
+		for _, step := range token.Steps {
+			word := e.Words[step.Word]
+			switch step.Kind {
+			case StepMatched, StepIncomplete, StepAlreadyMatched:
+				fmt.Fprintf(&res, "  %s: %s\n", word, step.Kind)
+			default:
+				fmt.Fprintf(&res, "  %s: %s on %q, at letter %d, skipped %d of %d\n", word, step.Kind, step.Letter, step.WordOffset, step.SkippedChars, step.AllowedOffset)
+			}
+		}
+	}
+
+	for idx, word := range e.Words {
+		if e.WordMatchedBy[idx] == -1 {
+			fmt.Fprintf(&res, "word %q not matched\n", word)
+		} else {
+			fmt.Fprintf(&res, "word %q matched by %q\n", word, e.Tokens[e.WordMatchedBy[idx]].Text)
+		}
+	}
+	if e.Matched {
+		fmt.Fprintf(&res, "sentence matched, Match returns %d\n", e.MatchResult)
+	} else {
+		fmt.Fprintf(&res, "sentence not matched, Match returns %d\n", e.MatchResult)
+	}
+
+	return res.String()
+}
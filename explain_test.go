@@ -0,0 +1,61 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	m := NewMatcher("apple", "banana shake")
+
+	explanation, err := m.Explain("I want a bannana shak", 1)
+	a.NoError(t, err)
+	a.Equal(t, 1, explanation.Sentence)
+	a.Equal(t, []string{"banana", "shake"}, explanation.Words)
+	a.Equal(t, []string{"I", "want", "a", "bannana", "shak"}, tokenTexts(explanation.Tokens))
+	a.Equal(t, []int{-1, 4}, explanation.WordMatchedBy)
+	a.False(t, explanation.Matched)
+	a.Equal(t, -1, explanation.MatchResult)
+
+	bannana := explanation.Tokens[3].Steps
+	a.Equal(t, Step{Kind: StepStarted, Word: 0, Letter: 'b', AllowedOffset: 2}, bannana[0])
+	a.Equal(t, StepDropped, bannana[len(bannana)-1].Kind)
+
+	shak := explanation.Tokens[4].Steps
+	a.Equal(t, StepMatched, shak[len(shak)-1].Kind)
+	a.Contains(t, explanation.String(), `word "shake" matched by "shak"`)
+
+	explanation, err = m.Explain("banana shake", 1)
+	a.NoError(t, err)
+	a.True(t, explanation.Matched)
+	a.Equal(t, []int{0, 1}, explanation.WordMatchedBy)
+	a.Equal(t, 1, explanation.MatchResult)
+
+	_, err = m.Explain("banana", 2)
+	a.Error(t, err)
+}
+
+func TestExplainAlreadyMatched(t *testing.T) {
+	m := NewMatcher("banana shake")
+
+	explanation, err := m.Explain("banana banana shake", 0)
+	a.NoError(t, err)
+	a.Equal(t, []int{0, 2}, explanation.WordMatchedBy)
+	steps := explanation.Tokens[1].Steps
+	a.Equal(t, Step{Kind: StepMatched, Word: 0, WordOffset: 5, AllowedOffset: 2}, steps[len(steps)-2])
+	a.Equal(t, Step{Kind: StepAlreadyMatched, Word: 0, WordOffset: 5, AllowedOffset: 2}, steps[len(steps)-1])
+}
+
+func TestTokenize(t *testing.T) {
+	a.Equal(t, []string{"foo", "bar", "coördinator", "a“b"}, tokenTexts(tokenize(" foo,bar  coördinator “a“b”")))
+	a.Equal(t, []string{}, tokenTexts(tokenize("")))
+}
+
+func tokenTexts(tokens []ExplainToken) []string {
+	res := []string{}
+	for _, token := range tokens {
+		res = append(res, token.Text)
+	}
+	return res
+}
@@ -0,0 +1,383 @@
+package fuzzymatcher
+
+import (
+	"errors"
+	"io"
+	"os"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// An index file contains the arrays of a matcher exactly as they are in memory so they can be used without decoding them
+// The file starts with indexHeader followed by the sections, every section starts at a multiple of indexAlign
+// As the arrays are stored in the byte order and layout of the machine a index can only be loaded on a machine with the same architecture
+
+var indexMagic = [8]byte{'f', 'z', 'm', 'i', 'n', 'd', 'e', 'x'}
+
+const (
+	indexVersion   = 1
+	indexByteOrder = 0x01020304
+	indexAlign     = 8
+)
+
+const (
+	indexSectionSentences = iota
+	indexSectionVocabulary
+	indexSectionSentenceWords
+	indexSectionLetters
+	indexSectionPostings
+	indexSectionPaths
+	indexSectionPathStarts
+	indexSectionUnicodePathLetters
+	// indexSectionPrefilterStarts contains for every prefilter bucket where it starts within indexSectionPrefilter
+	indexSectionPrefilterStarts
+	indexSectionPrefilter
+	indexSections
+)
+
+type indexSection struct {
+	Offset uint64
+	Len    uint64 // The amount of items, not bytes
+}
+
+type indexHeader struct {
+	Magic     [8]byte
+	Version   uint32
+	ByteOrder uint32
+
+	// The sizes of the stored types, used to detect indexes written on a different architecture
+	SentenceSize uint32
+	WordSize     uint32
+	PostingSize  uint32
+	PathSize     uint32
+
+	HasPathsWithRuneSelf bool
+	PrefilterKeys        uint64
+	Sections             [indexSections]indexSection
+}
+
+// indexItemSizes contains the size of a single item of every section
+var indexItemSizes = [indexSections]uintptr{
+	indexSectionSentences:          unsafe.Sizeof(sentenceT{}),
+	indexSectionVocabulary:         unsafe.Sizeof(wordEntry{}),
+	indexSectionSentenceWords:      unsafe.Sizeof(int32(0)),
+	indexSectionLetters:            unsafe.Sizeof(rune(0)),
+	indexSectionPostings:           unsafe.Sizeof(posting{}),
+	indexSectionPaths:              unsafe.Sizeof(pathToWord{}),
+	indexSectionPathStarts:         unsafe.Sizeof(int32(0)),
+	indexSectionUnicodePathLetters: unsafe.Sizeof(rune(0)),
+	indexSectionPrefilterStarts:    unsafe.Sizeof(int32(0)),
+	indexSectionPrefilter:          unsafe.Sizeof(int32(0)),
+}
+
+// ErrInvalidIndex is returned when loading a index that is not written by WriteIndex, is truncated or is written on a different architecture
+var ErrInvalidIndex = errors.New("invalid index")
+
+// memoryBytes returns the memory of n items of size starting at ptr as bytes
+func memoryBytes(ptr unsafe.Pointer, n int, size uintptr) []byte {
+	if n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(ptr), n*int(size))
+}
+
+// WriteIndex writes the matcher in a format that can be loaded without decoding it using LoadIndex or OpenIndex
+// Only the sentences are stored, settings like Limits, TieBreak, Priorities, Prefilter and Engine have to be set again after loading
+func (m *Matcher) WriteIndex(w io.Writer) error {
+	prefilterStarts := make([]int32, len(m.PrefilterBuckets)+1)
+	prefilter := []int32{}
+	for idx, bucket := range m.PrefilterBuckets {
+		prefilterStarts[idx] = int32(len(prefilter))
+		prefilter = append(prefilter, bucket...)
+	}
+	prefilterStarts[len(m.PrefilterBuckets)] = int32(len(prefilter))
+
+	sections := [indexSections][]byte{}
+	lens := [indexSections]int{
+		indexSectionSentences:          len(m.Sentences),
+		indexSectionVocabulary:         len(m.Vocabulary),
+		indexSectionSentenceWords:      len(m.SentenceWords),
+		indexSectionLetters:            len(m.Letters),
+		indexSectionPostings:           len(m.Postings),
+		indexSectionPaths:              len(m.Paths),
+		indexSectionPathStarts:         len(m.PathStarts),
+		indexSectionUnicodePathLetters: len(m.UnicodePathLetters),
+		indexSectionPrefilterStarts:    len(prefilterStarts),
+		indexSectionPrefilter:          len(prefilter),
+	}
+	if len(m.Sentences) > 0 {
+		sections[indexSectionSentences] = memoryBytes(unsafe.Pointer(&m.Sentences[0]), len(m.Sentences), indexItemSizes[indexSectionSentences])
+	}
+	if len(m.Vocabulary) > 0 {
+		sections[indexSectionVocabulary] = memoryBytes(unsafe.Pointer(&m.Vocabulary[0]), len(m.Vocabulary), indexItemSizes[indexSectionVocabulary])
+	}
+	if len(m.SentenceWords) > 0 {
+		sections[indexSectionSentenceWords] = memoryBytes(unsafe.Pointer(&m.SentenceWords[0]), len(m.SentenceWords), indexItemSizes[indexSectionSentenceWords])
+	}
+	if len(m.Letters) > 0 {
+		sections[indexSectionLetters] = memoryBytes(unsafe.Pointer(&m.Letters[0]), len(m.Letters), indexItemSizes[indexSectionLetters])
+	}
+	if len(m.Postings) > 0 {
+		sections[indexSectionPostings] = memoryBytes(unsafe.Pointer(&m.Postings[0]), len(m.Postings), indexItemSizes[indexSectionPostings])
+	}
+	if len(m.Paths) > 0 {
+		sections[indexSectionPaths] = memoryBytes(unsafe.Pointer(&m.Paths[0]), len(m.Paths), indexItemSizes[indexSectionPaths])
+	}
+	if len(m.PathStarts) > 0 {
+		sections[indexSectionPathStarts] = memoryBytes(unsafe.Pointer(&m.PathStarts[0]), len(m.PathStarts), indexItemSizes[indexSectionPathStarts])
+	}
+	if len(m.UnicodePathLetters) > 0 {
+		sections[indexSectionUnicodePathLetters] = memoryBytes(unsafe.Pointer(&m.UnicodePathLetters[0]), len(m.UnicodePathLetters), indexItemSizes[indexSectionUnicodePathLetters])
+	}
+	sections[indexSectionPrefilterStarts] = memoryBytes(unsafe.Pointer(&prefilterStarts[0]), len(prefilterStarts), indexItemSizes[indexSectionPrefilterStarts])
+	if len(prefilter) > 0 {
+		sections[indexSectionPrefilter] = memoryBytes(unsafe.Pointer(&prefilter[0]), len(prefilter), indexItemSizes[indexSectionPrefilter])
+	}
+
+	header := indexHeader{
+		Magic:                indexMagic,
+		Version:              indexVersion,
+		ByteOrder:            indexByteOrder,
+		SentenceSize:         uint32(unsafe.Sizeof(sentenceT{})),
+		WordSize:             uint32(unsafe.Sizeof(wordEntry{})),
+		PostingSize:          uint32(unsafe.Sizeof(posting{})),
+		PathSize:             uint32(unsafe.Sizeof(pathToWord{})),
+		HasPathsWithRuneSelf: m.HasPathsWithRuneSelf,
+		PrefilterKeys:        m.PrefilterKeys,
+	}
+	offset := indexAlignUp(int(unsafe.Sizeof(header)))
+	for idx, section := range sections {
+		header.Sections[idx] = indexSection{Offset: uint64(offset), Len: uint64(lens[idx])}
+		offset = indexAlignUp(offset + len(section))
+	}
+
+	written := int(unsafe.Sizeof(header))
+	_, err := w.Write(memoryBytes(unsafe.Pointer(&header), 1, unsafe.Sizeof(header)))
+	if err != nil {
+		return err
+	}
+	padding := make([]byte, indexAlign)
+	for idx, section := range sections {
+		_, err = w.Write(padding[:int(header.Sections[idx].Offset)-written])
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(section)
+		if err != nil {
+			return err
+		}
+		written = int(header.Sections[idx].Offset) + len(section)
+	}
+	return nil
+}
+
+func indexAlignUp(n int) int {
+	return (n + indexAlign - 1) / indexAlign * indexAlign
+}
+
+// LoadIndex creates a read only matcher from a index written by WriteIndex
+// The matcher uses data directly so data must not be modified while the matcher is in use,
+// only if data is not aligned to 8 bytes it's copied first
+// All references between the arrays are validated so a corrupted index returns ErrInvalidIndex instead of making the matcher read out of bounds
+func LoadIndex(data []byte) (*Matcher, error) {
+	if len(data) < int(unsafe.Sizeof(indexHeader{})) {
+		return nil, ErrInvalidIndex
+	}
+	if uintptr(unsafe.Pointer(&data[0]))%indexAlign != 0 {
+		aligned := make([]uint64, (len(data)+indexAlign-1)/indexAlign)
+		alignedData := memoryBytes(unsafe.Pointer(&aligned[0]), len(data), 1)
+		copy(alignedData, data)
+		data = alignedData
+	}
+
+	if data[unsafe.Offsetof(indexHeader{}.HasPathsWithRuneSelf)] > 1 {
+		// Not a valid bool
+		return nil, ErrInvalidIndex
+	}
+	header := *(*indexHeader)(unsafe.Pointer(&data[0]))
+	if header.Magic != indexMagic ||
+		header.Version != indexVersion ||
+		header.ByteOrder != indexByteOrder ||
+		header.SentenceSize != uint32(unsafe.Sizeof(sentenceT{})) ||
+		header.WordSize != uint32(unsafe.Sizeof(wordEntry{})) ||
+		header.PostingSize != uint32(unsafe.Sizeof(posting{})) ||
+		header.PathSize != uint32(unsafe.Sizeof(pathToWord{})) {
+		return nil, ErrInvalidIndex
+	}
+
+	pointers := [indexSections]unsafe.Pointer{}
+	lens := [indexSections]int{}
+	for idx, section := range header.Sections {
+		size := uint64(indexItemSizes[idx])
+		if section.Offset%indexAlign != 0 || section.Offset > uint64(len(data)) || section.Len > (uint64(len(data))-section.Offset)/size {
+			return nil, ErrInvalidIndex
+		}
+		lens[idx] = int(section.Len)
+		if section.Len > 0 {
+			pointers[idx] = unsafe.Pointer(&data[section.Offset])
+		}
+	}
+
+	m := &Matcher{
+		HasPathsWithRuneSelf: header.HasPathsWithRuneSelf,
+		PrefilterKeys:        header.PrefilterKeys,
+	}
+	if lens[indexSectionSentences] > 0 {
+		m.Sentences = unsafe.Slice((*sentenceT)(pointers[indexSectionSentences]), lens[indexSectionSentences])
+	}
+	if lens[indexSectionVocabulary] > 0 {
+		m.Vocabulary = unsafe.Slice((*wordEntry)(pointers[indexSectionVocabulary]), lens[indexSectionVocabulary])
+	}
+	if lens[indexSectionSentenceWords] > 0 {
+		m.SentenceWords = unsafe.Slice((*int32)(pointers[indexSectionSentenceWords]), lens[indexSectionSentenceWords])
+	}
+	if lens[indexSectionLetters] > 0 {
+		m.Letters = unsafe.Slice((*rune)(pointers[indexSectionLetters]), lens[indexSectionLetters])
+	}
+	if lens[indexSectionPostings] > 0 {
+		m.Postings = unsafe.Slice((*posting)(pointers[indexSectionPostings]), lens[indexSectionPostings])
+	}
+	if lens[indexSectionPaths] > 0 {
+		m.Paths = unsafe.Slice((*pathToWord)(pointers[indexSectionPaths]), lens[indexSectionPaths])
+	}
+	if lens[indexSectionPathStarts] > 0 {
+		m.PathStarts = unsafe.Slice((*int32)(pointers[indexSectionPathStarts]), lens[indexSectionPathStarts])
+	}
+	if lens[indexSectionUnicodePathLetters] > 0 {
+		m.UnicodePathLetters = unsafe.Slice((*rune)(pointers[indexSectionUnicodePathLetters]), lens[indexSectionUnicodePathLetters])
+	}
+
+	if !m.validIndexReferences() {
+		return nil, ErrInvalidIndex
+	}
+
+	if lens[indexSectionPrefilterStarts] != len(m.PrefilterBuckets)+1 {
+		return nil, ErrInvalidIndex
+	}
+	prefilterStarts := unsafe.Slice((*int32)(pointers[indexSectionPrefilterStarts]), lens[indexSectionPrefilterStarts])
+	prefilter := []int32{}
+	if lens[indexSectionPrefilter] > 0 {
+		prefilter = unsafe.Slice((*int32)(pointers[indexSectionPrefilter]), lens[indexSectionPrefilter])
+	}
+	for idx := range m.PrefilterBuckets {
+		start, end := prefilterStarts[idx], prefilterStarts[idx+1]
+		if start < 0 || start > end || int(end) > len(prefilter) {
+			return nil, ErrInvalidIndex
+		}
+		if start != end {
+			// Limit the capacity so a append can never write into data
+			m.PrefilterBuckets[idx] = prefilter[start:end:end]
+		}
+	}
+	for _, sentenceIdx := range prefilter {
+		if sentenceIdx < 0 || int(sentenceIdx) >= len(m.Sentences) {
+			return nil, ErrInvalidIndex
+		}
+	}
+
+	return m, nil
+}
+
+// validIndexReferences checks if every index into another array of the matcher is within the bounds of that array
+func (m *Matcher) validIndexReferences() bool {
+	inRange := func(start, end int32, len int) bool {
+		return start >= 0 && start <= end && int(end) <= len
+	}
+
+	for _, sentence := range m.Sentences {
+		if !inRange(sentence.WordsStart, sentence.WordsEnd, len(m.SentenceWords)) || sentence.IdxInNewMatcherInput < 0 {
+			return false
+		}
+	}
+	for _, word := range m.SentenceWords {
+		if word < 0 || int(word) >= len(m.Vocabulary) {
+			return false
+		}
+	}
+	for _, p := range m.Postings {
+		if p.Sentence < 0 || int(p.Sentence) >= len(m.Sentences) || p.Word < 0 ||
+			int(p.Word) >= m.Sentences[p.Sentence].wordCount() {
+			return false
+		}
+	}
+	for wordIdx, word := range m.Vocabulary {
+		// The allowed offset is derived from the length, it limits how far the letters are read
+		if word.len <= 0 || word.allowedOffset != wordAllowedOffset(word.len) ||
+			!inRange(word.LettersStart, word.LettersStart+word.len, len(m.Letters)) ||
+			!inRange(word.PostingsStart, word.PostingsEnd, len(m.Postings)) {
+			return false
+		}
+		// The postings of a word have to point back to the word
+		for _, p := range m.Postings[word.PostingsStart:word.PostingsEnd] {
+			if int(m.SentenceWords[m.Sentences[p.Sentence].WordsStart+p.Word]) != wordIdx {
+				return false
+			}
+		}
+	}
+	for _, path := range m.Paths {
+		if path.Word < 0 || int(path.Word) >= len(m.Vocabulary) ||
+			path.WordOffset < 0 || path.WordOffset >= m.Vocabulary[path.Word].len {
+			return false
+		}
+	}
+
+	// The path lookup table is used for every input letter so make sure it can't be read out of bounds
+	if len(m.PathStarts) != utf8.RuneSelf+len(m.UnicodePathLetters)+1 {
+		return false
+	}
+	// The unicode letters are binary searched
+	for idx := 1; idx < len(m.UnicodePathLetters); idx++ {
+		if m.UnicodePathLetters[idx] <= m.UnicodePathLetters[idx-1] {
+			return false
+		}
+	}
+	for idx, start := range m.PathStarts {
+		if start < 0 || int(start) > len(m.Paths) || (idx > 0 && start < m.PathStarts[idx-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// OpenIndex opens a index file written by WriteIndex by memory mapping it
+// The matcher is read only and queries the mapped file directly so opening is fast and
+// all processes that open the same file share the same memory through the page cache
+// Call Close once the matcher is no longer used
+func OpenIndex(path string) (*Matcher, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < int64(unsafe.Sizeof(indexHeader{})) {
+		return nil, ErrInvalidIndex
+	}
+
+	data, unmap, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	m, err := LoadIndex(data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	m.unmap = unmap
+	return m, nil
+}
+
+// Close releases the memory mapped file of a matcher opened by OpenIndex, the matcher can not be used afterwards
+// For other matchers Close does nothing
+func (m *Matcher) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+	unmap := m.unmap
+	m.unmap = nil
+	return unmap()
+}
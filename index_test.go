@@ -0,0 +1,211 @@
+package fuzzymatcher
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func writeTestIndex(t *testing.T, m *Matcher) []byte {
+	buf := bytes.NewBuffer(nil)
+	a.NoError(t, m.WriteIndex(buf))
+	return buf.Bytes()
+}
+
+func TestLoadIndex(t *testing.T) {
+	sentences := []string{
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+		"123 Avenue Road",
+		"coördinator",
+		"Ætherische olie",
+	}
+	m := NewMatcher(sentences...)
+	loaded, err := LoadIndex(writeTestIndex(t, m))
+	a.NoError(t, err)
+
+	inputs := []string{
+		"nothing",
+		"i love trees",
+		"are the best fruit bananas?",
+		"123 Avvenue Road, Greenmeadows",
+		"coordinator",
+		"aetherische olie",
+		"ætherische olie",
+	}
+	for _, engine := range []Engine{EnginePaths, EngineTrie} {
+		m.Engine = engine
+		loaded.Engine = engine
+		for _, input := range inputs {
+			a.Equal(t, m.Match(input), loaded.Match(input), input)
+			a.Equal(t, m.MatchAll(input), loaded.MatchAll(input), input)
+			a.Equal(t, m.MayMatch(input), loaded.MayMatch(input), input)
+		}
+	}
+	a.Equal(t, m.Complete("bana", 0), loaded.Complete("bana", 0))
+	a.Equal(t, m.Suggest("bananna", 3), loaded.Suggest("bananna", 3))
+	a.Equal(t, m.Analyze(), loaded.Analyze())
+	a.Equal(t, m.PrefilterBuckets, loaded.PrefilterBuckets)
+
+	empty, err := LoadIndex(writeTestIndex(t, NewMatcher()))
+	a.NoError(t, err)
+	a.Equal(t, -1, empty.Match("banana"))
+}
+
+func TestLoadIndexUnaligned(t *testing.T) {
+	m := NewMatcher("I love trees", "banana")
+	data := writeTestIndex(t, m)
+
+	unaligned := make([]byte, len(data)+1)
+	copy(unaligned[1:], data)
+	loaded, err := LoadIndex(unaligned[1:])
+	a.NoError(t, err)
+	a.Equal(t, 1, loaded.Match("a banana"))
+}
+
+func TestLoadInvalidIndex(t *testing.T) {
+	data := writeTestIndex(t, NewMatcher("I love trees", "banana"))
+
+	for name, invalid := range map[string][]byte{
+		"empty":     {},
+		"truncated": data[:len(data)-4],
+		"header":    data[:10],
+		"magic":     append([]byte("notindex"), data[8:]...),
+	} {
+		_, err := LoadIndex(invalid)
+		a.ErrorIs(t, err, ErrInvalidIndex, name)
+	}
+}
+
+func TestLoadCorruptedIndex(t *testing.T) {
+	data := writeTestIndex(t, NewMatcher("I love trees", "bananas are the best fruit", "banana"))
+	header := (*indexHeader)(unsafe.Pointer(&data[0]))
+
+	// field returns the offset within data of a field of the first item of a section
+	field := func(section int, fieldOffset uintptr) int {
+		return int(header.Sections[section].Offset) + int(fieldOffset)
+	}
+	fields := map[string]int{
+		"sentence words start":  field(indexSectionSentences, unsafe.Offsetof(sentenceT{}.WordsStart)),
+		"sentence words end":    field(indexSectionSentences, unsafe.Offsetof(sentenceT{}.WordsEnd)),
+		"sentence word":         field(indexSectionSentenceWords, 0),
+		"word letters start":    field(indexSectionVocabulary, unsafe.Offsetof(wordEntry{}.LettersStart)),
+		"word postings start":   field(indexSectionVocabulary, unsafe.Offsetof(wordEntry{}.PostingsStart)),
+		"word postings end":     field(indexSectionVocabulary, unsafe.Offsetof(wordEntry{}.PostingsEnd)),
+		"word len":              field(indexSectionVocabulary, unsafe.Offsetof(wordEntry{}.len)),
+		"word allowed offset":   field(indexSectionVocabulary, unsafe.Offsetof(wordEntry{}.allowedOffset)),
+		"posting sentence":      field(indexSectionPostings, unsafe.Offsetof(posting{}.Sentence)),
+		"posting word":          field(indexSectionPostings, unsafe.Offsetof(posting{}.Word)),
+		"path word":             field(indexSectionPaths, unsafe.Offsetof(pathToWord{}.Word)),
+		"path word offset":      field(indexSectionPaths, unsafe.Offsetof(pathToWord{}.WordOffset)),
+		"path start":            field(indexSectionPathStarts, 4*'b'),
+		"prefilter sentence id": field(indexSectionPrefilter, 0),
+	}
+
+	for name, offset := range fields {
+		for _, value := range []int32{-1, 1 << 20} {
+			corrupted := append([]byte(nil), data...)
+			*(*int32)(unsafe.Pointer(&corrupted[offset])) = value
+			_, err := LoadIndex(corrupted)
+			a.ErrorIs(t, err, ErrInvalidIndex, "%s set to %d", name, value)
+		}
+	}
+
+	// The first posting is the word "i" of the first sentence, point it to a word after the sentence and to an other word of the sentence
+	for _, value := range []int32{9, 1} {
+		corrupted := append([]byte(nil), data...)
+		*(*int32)(unsafe.Pointer(&corrupted[fields["posting word"]])) = value
+		_, err := LoadIndex(corrupted)
+		a.ErrorIs(t, err, ErrInvalidIndex, "posting word set to %d", value)
+	}
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[unsafe.Offsetof(indexHeader{}.HasPathsWithRuneSelf)] = 2
+	_, err := LoadIndex(corrupted)
+	a.ErrorIs(t, err, ErrInvalidIndex, "HasPathsWithRuneSelf set to 2")
+
+	// Flipping any byte of the sections either results in a error or a matcher that can be used without panicking
+	for offset := int(unsafe.Sizeof(indexHeader{})); offset < len(data); offset++ {
+		corrupted := append([]byte(nil), data...)
+		corrupted[offset] ^= 0xff
+		m, err := LoadIndex(corrupted)
+		if err == nil {
+			m.MatchAll("i love bananas and trees")
+			m.Complete("bana", 0)
+			m.Scan("i love bananas and trees")
+			for sentence := range m.Sentences {
+				_, _ = m.Explain("i love bananas and trees", sentence)
+			}
+		}
+	}
+}
+
+func TestLoadIndexUnsortedUnicodePathLetters(t *testing.T) {
+	m := NewMatcher("şu", "ğa")
+	a.Len(t, m.UnicodePathLetters, 2)
+	data := writeTestIndex(t, m)
+	header := (*indexHeader)(unsafe.Pointer(&data[0]))
+
+	letters := unsafe.Slice((*rune)(unsafe.Pointer(&data[header.Sections[indexSectionUnicodePathLetters].Offset])), 2)
+	letters[0], letters[1] = letters[1], letters[0]
+	_, err := LoadIndex(data)
+	a.ErrorIs(t, err, ErrInvalidIndex)
+}
+
+func TestOpenIndex(t *testing.T) {
+	m := NewMatcher(benchmarkSentences(1_000)...)
+	path := filepath.Join(t.TempDir(), "index")
+	a.NoError(t, os.WriteFile(path, writeTestIndex(t, m), 0644))
+
+	loaded, err := OpenIndex(path)
+	a.NoError(t, err)
+	input := "i would like to buy a " + m.words(&m.Sentences[10])[0]
+	a.Equal(t, m.Match(input), loaded.Match(input))
+	a.Equal(t, m.MatchAll(input), loaded.MatchAll(input))
+	a.NoError(t, loaded.Close())
+	a.NoError(t, loaded.Close())
+	a.NoError(t, m.Close())
+
+	_, err = OpenIndex(filepath.Join(t.TempDir(), "does-not-exist"))
+	a.Error(t, err)
+
+	emptyPath := filepath.Join(t.TempDir(), "empty")
+	a.NoError(t, os.WriteFile(emptyPath, nil, 0644))
+	_, err = OpenIndex(emptyPath)
+	a.ErrorIs(t, err, ErrInvalidIndex)
+}
+
+func BenchmarkOpenIndex(b *testing.B) {
+	m := NewMatcher(benchmarkSentences(100_000)...)
+	path := filepath.Join(b.TempDir(), "index")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	err = m.WriteIndex(f)
+	f.Close()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("new-matcher", func(b *testing.B) {
+		sentences := benchmarkSentences(100_000)
+		for i := 0; i < b.N; i++ {
+			NewMatcher(sentences...)
+		}
+	})
+	b.Run("open-index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			loaded, err := OpenIndex(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			loaded.Close()
+		}
+	})
+}
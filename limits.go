@@ -0,0 +1,93 @@
+package fuzzymatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// contextCheckInterval is the amount of bytes of input that are matched before checking if the context was canceled
+const contextCheckInterval = 4096
+
+var (
+	// ErrInputTooLarge is wrapped in a LimitError when the input is larger than Limits.MaxInputBytes
+	ErrInputTooLarge = errors.New("input too large")
+	// ErrTooManyInProgressMatches is wrapped in a LimitError when an input word matched more words than Limits.MaxInProgressMatches
+	ErrTooManyInProgressMatches = errors.New("too many in progress matches")
+)
+
+// Limits guards a matcher against inputs that take to long to match
+// Limits are only applied by the functions that can return an error like MatchContext
+type Limits struct {
+	// MaxInputBytes is the maximum length of an input in bytes, 0 means no limit
+	MaxInputBytes int
+	// MaxInProgressMatches is the maximum amount of sentence words an input word can be matching at the same time, 0 means no limit
+	// With EngineTrie it's the maximum amount of vocabulary words an input word is compared with
+	MaxInProgressMatches int
+}
+
+// LimitError is returned when an input exceeds one of the limits of the matcher
+// Use errors.Is with ErrInputTooLarge or ErrTooManyInProgressMatches to check which limit was exceeded
+type LimitError struct {
+	Err   error
+	Limit int
+	Value int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %d exceeds the limit of %d", e.Err.Error(), e.Value, e.Limit)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// MatchContext is the same as Match but stops when the context is canceled or when the input exceeds one of the matchers limits
+// Returns the context error or a *LimitError if the matching was stopped
+func (m *Matcher) MatchContext(ctx context.Context, sentence string) (int, error) {
+	res := -1
+	err := m.matchContext(ctx, sentence, func(state *matchState) {
+		res = state.first(sentence)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return res, nil
+}
+
+// MatchAllContext is the same as MatchAll but stops when the context is canceled or when the input exceeds one of the matchers limits
+// Returns the context error or a *LimitError if the matching was stopped
+func (m *Matcher) MatchAllContext(ctx context.Context, sentence string) ([]Match, error) {
+	var res []Match
+	err := m.matchContext(ctx, sentence, func(state *matchState) {
+		res = state.all(sentence)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// matchContext calls match with a match state that has the context and limits applied
+func (m *Matcher) matchContext(ctx context.Context, sentence string, match func(state *matchState)) error {
+	if m.Limits.MaxInputBytes > 0 && len(sentence) > m.Limits.MaxInputBytes {
+		return &LimitError{
+			Err:   ErrInputTooLarge,
+			Limit: m.Limits.MaxInputBytes,
+			Value: len(sentence),
+		}
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	state := m.getState()
+	defer m.putState(state)
+
+	state.ctx = ctx
+	state.maxInProgressMatches = m.Limits.MaxInProgressMatches
+	match(state)
+	return state.err
+}
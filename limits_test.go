@@ -0,0 +1,97 @@
+package fuzzymatcher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestMatchContext(t *testing.T) {
+	m := NewMatcher("I love trees", "banana")
+
+	res, err := m.MatchContext(context.Background(), "i love trees")
+	a.NoError(t, err)
+	a.Equal(t, 0, res)
+
+	res, err = m.MatchContext(context.Background(), "nothing")
+	a.NoError(t, err)
+	a.Equal(t, -1, res)
+
+	matches, err := m.MatchAllContext(context.Background(), "i love trees and bananas")
+	a.NoError(t, err)
+	a.Len(t, matches, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = m.MatchContext(ctx, "i love trees")
+	a.Equal(t, context.Canceled, err)
+	a.Equal(t, -1, res)
+}
+
+// cancelAfterContext is a context that is canceled after Err is called a number of times
+type cancelAfterContext struct {
+	context.Context
+	calls int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.calls--
+	if c.calls < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestMatchContextCanceledWhileMatching(t *testing.T) {
+	m := NewMatcher("banana")
+
+	ctx := &cancelAfterContext{Context: context.Background(), calls: 2}
+	input := strings.Repeat("nothing to see here ", contextCheckInterval) + "banana"
+	res, err := m.MatchContext(ctx, input)
+	a.Equal(t, context.Canceled, err)
+	a.Equal(t, -1, res)
+
+	// The returned match state should not be canceled anymore
+	a.Equal(t, 0, m.Match(input))
+}
+
+func TestMatchContextLimits(t *testing.T) {
+	m := NewMatcher("banana", "bananas are great", "bandana", "band", "ban")
+	m.Limits = Limits{
+		MaxInputBytes:        20,
+		MaxInProgressMatches: 3,
+	}
+
+	res, err := m.MatchContext(context.Background(), "this input is way too long")
+	a.True(t, errors.Is(err, ErrInputTooLarge))
+	a.Equal(t, -1, res)
+	var limitErr *LimitError
+	a.True(t, errors.As(err, &limitErr))
+	a.Equal(t, 20, limitErr.Limit)
+	a.Equal(t, 26, limitErr.Value)
+
+	_, err = m.MatchAllContext(context.Background(), "i like bananas")
+	a.True(t, errors.Is(err, ErrTooManyInProgressMatches))
+
+	res, err = m.MatchContext(context.Background(), "nothing")
+	a.NoError(t, err)
+	a.Equal(t, -1, res)
+
+	// Match does not apply the limits
+	a.Equal(t, 0, m.Match("i like bananas"))
+
+	// With EngineTrie the vocabulary words compared with a input word are limited
+	m.Engine = EngineTrie
+	_, err = m.MatchAllContext(context.Background(), "i like bananas")
+	a.True(t, errors.Is(err, ErrTooManyInProgressMatches))
+	res, err = m.MatchContext(context.Background(), "are great")
+	a.NoError(t, err)
+	a.Equal(t, -1, res)
+	m.Limits.MaxInProgressMatches = 0
+	res, err = m.MatchContext(context.Background(), "i like bananas")
+	a.NoError(t, err)
+	a.Equal(t, 0, res)
+}
@@ -0,0 +1,235 @@
+package fuzzymatcher
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// DefaultLinkThreshold is used by Link if LinkOptions.Threshold is not set
+const DefaultLinkThreshold = 0.6
+
+// LinkOptions changes the behavior of Link
+type LinkOptions struct {
+	// Threshold is the minimum score between 0 and 1 of a pair, defaults to DefaultLinkThreshold
+	Threshold float64
+	// MaxCandidates is the maximum amount of pairs per left record, the pairs with the highest score are kept
+	// 0 means no limit
+	MaxCandidates int
+	// Engine is the engine used to compare the words of the fields
+	Engine Engine
+}
+
+// LinkField explains how a field of the left record matched the field with the same name of the right record
+type LinkField struct {
+	Name string
+	// Similarity is the fraction of the words of both values that fuzzy match a word of the other value
+	Similarity float64
+	// LeftMatched and RightMatched contain the indexes of the words of the left and right value that are matched by the other value
+	// Use WordSpans to find them in the values
+	LeftMatched  []int
+	RightMatched []int
+}
+
+// LinkPair is a left and right record that probably describe the same entity
+type LinkPair struct {
+	// Left and Right are the indexes of the records within the Link input
+	Left  int
+	Right int
+	// Score is the average similarity of the fields weighted by the boost of the left fields
+	Score float64
+	// Fields contains a explanation for every field of the left record in the same order
+	Fields []LinkField
+}
+
+// linkField is a matcher over the values of one field of the right records
+type linkField struct {
+	Matcher *Matcher
+	// recordBySentence maps the sentences of the matcher to the right records
+	recordBySentence []int
+	// words contains the amount of words of the value of every right record
+	words []int
+	// leftMatched contains per sentence the left words that matched the sentence, it's reset after every left value
+	leftMatched []uint64
+}
+
+// Link finds the pairs of left and right records that probably describe the same entity, for example the same customer in two datasets
+// Fields are compared with the field with the same name of the other record, the similarity of two fields is calculated like ClusterItems does
+// A matcher is build over every field of the right records that the field values of the left records are matched against,
+// so only records that share words are compared instead of comparing every left record with every right record
+// If a required field of the left record does not match at all the pair is not returned
+// The pairs are ordered by left record and then by score where the highest score comes first
+func Link(left, right []Record, opts LinkOptions) []LinkPair {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultLinkThreshold
+	}
+
+	fields := map[string]*linkField{}
+	fieldByName := func(name string) *linkField {
+		field, ok := fields[name]
+		if ok {
+			return field
+		}
+
+		field = &linkField{recordBySentence: []int{}}
+		values := []string{}
+		for recordIdx, record := range right {
+			for _, f := range record.Fields {
+				if f.Name == name {
+					values = append(values, f.Value)
+					field.recordBySentence = append(field.recordBySentence, recordIdx)
+					break
+				}
+			}
+		}
+		field.Matcher = NewMatcher(values...)
+		field.Matcher.Engine = opts.Engine
+		field.leftMatched = make([]uint64, len(field.Matcher.Sentences))
+		field.words = make([]int, len(right))
+		for sentenceIdx := range field.Matcher.Sentences {
+			sentence := &field.Matcher.Sentences[sentenceIdx]
+			field.words[field.recordBySentence[sentence.IdxInNewMatcherInput]] = bits.OnesCount64(sentence.indexSum())
+		}
+		fields[name] = field
+		return field
+	}
+
+	// leftMatched and rightMatched contain per right record and field of the left record the matched words,
+	// they are reset for the touched right records after every left record
+	leftMatched := []uint64{}
+	rightMatched := []uint64{}
+	touched := []int{}
+	isTouched := make([]bool, len(right))
+
+	res := []LinkPair{}
+	for leftIdx, record := range left {
+		fieldsLen := len(record.Fields)
+		if len(right)*fieldsLen > len(leftMatched) {
+			leftMatched = make([]uint64, len(right)*fieldsLen)
+			rightMatched = make([]uint64, len(right)*fieldsLen)
+		}
+		leftWords := make([]int, fieldsLen)
+
+		for fieldIdx, f := range record.Fields {
+			spans := WordSpans(f.Value)
+			leftWords[fieldIdx] = len(spans)
+			if leftWords[fieldIdx] > MaxWords {
+				leftWords[fieldIdx] = MaxWords
+			}
+			if len(spans) == 0 {
+				continue
+			}
+
+			field := fieldByName(f.Name)
+			m := field.Matcher
+			state := m.getState()
+			state.walk(f.Value, false, func(entry *inProgressMatch, p posting, start, end int) bool {
+				state.addWordIdxToSentence(entry, p, start, end)
+				if !state.Sentences[p.Sentence].Touched {
+					// The word is after the MaxWords limit of the sentence, the sentence is not reset so it must not be marked
+					return true
+				}
+				// The input word is the last word that starts before or at the start of the match
+				wordIdx := sort.Search(len(spans), func(i int) bool {
+					return spans[i].Start > start
+				}) - 1
+				if wordIdx >= 0 && wordIdx < MaxWords {
+					field.leftMatched[p.Sentence] |= 1 << uint(wordIdx)
+				}
+				return true
+			})
+
+			for _, sentenceIdx := range state.touched {
+				rightIdx := field.recordBySentence[m.Sentences[sentenceIdx].IdxInNewMatcherInput]
+				if !isTouched[rightIdx] {
+					isTouched[rightIdx] = true
+					touched = append(touched, rightIdx)
+				}
+				leftMatched[rightIdx*fieldsLen+fieldIdx] = field.leftMatched[sentenceIdx]
+				rightMatched[rightIdx*fieldsLen+fieldIdx] = state.Sentences[sentenceIdx].IndexSum
+				field.leftMatched[sentenceIdx] = 0
+			}
+			m.putState(state)
+		}
+
+		pairs := []LinkPair{}
+	candidates:
+		for _, rightIdx := range touched {
+			matched := rightIdx * fieldsLen
+			score := 0.0
+			totalBoost := 0.0
+			for fieldIdx, f := range record.Fields {
+				if f.Required && rightMatched[matched+fieldIdx] == 0 {
+					continue candidates
+				}
+				score += linkSimilarity(fields[f.Name], rightIdx, leftWords[fieldIdx], leftMatched[matched+fieldIdx], rightMatched[matched+fieldIdx]) * f.boost()
+				totalBoost += f.boost()
+			}
+			if totalBoost > 0 {
+				score /= totalBoost
+			}
+			if score < threshold {
+				continue
+			}
+
+			pair := LinkPair{
+				Left:   leftIdx,
+				Right:  rightIdx,
+				Score:  score,
+				Fields: make([]LinkField, fieldsLen),
+			}
+			for fieldIdx, f := range record.Fields {
+				pair.Fields[fieldIdx] = LinkField{
+					Name:         f.Name,
+					Similarity:   linkSimilarity(fields[f.Name], rightIdx, leftWords[fieldIdx], leftMatched[matched+fieldIdx], rightMatched[matched+fieldIdx]),
+					LeftMatched:  bitIndexes(leftMatched[matched+fieldIdx]),
+					RightMatched: bitIndexes(rightMatched[matched+fieldIdx]),
+				}
+			}
+			pairs = append(pairs, pair)
+		}
+
+		for _, rightIdx := range touched {
+			isTouched[rightIdx] = false
+			for idx := rightIdx * fieldsLen; idx < (rightIdx+1)*fieldsLen; idx++ {
+				leftMatched[idx] = 0
+				rightMatched[idx] = 0
+			}
+		}
+		touched = touched[:0]
+
+		sort.Slice(pairs, func(a, b int) bool {
+			if pairs[a].Score == pairs[b].Score {
+				return pairs[a].Right < pairs[b].Right
+			}
+			return pairs[a].Score > pairs[b].Score
+		})
+		if opts.MaxCandidates > 0 && len(pairs) > opts.MaxCandidates {
+			pairs = pairs[:opts.MaxCandidates]
+		}
+		res = append(res, pairs...)
+	}
+	return res
+}
+
+// linkSimilarity returns the fraction of the words of a left and right value that are matched by the other value
+// field is nil if none of the right records have the field
+func linkSimilarity(field *linkField, rightIdx int, leftWords int, leftMatched, rightMatched uint64) float64 {
+	rightWords := 0
+	if field != nil {
+		rightWords = field.words[rightIdx]
+	}
+	if leftWords+rightWords == 0 {
+		return 0
+	}
+	return float64(bits.OnesCount64(leftMatched)+bits.OnesCount64(rightMatched)) / float64(leftWords+rightWords)
+}
+
+// bitIndexes returns the indexes of the set bits
+func bitIndexes(value uint64) []int {
+	res := make([]int, 0, bits.OnesCount64(value))
+	for ; value != 0; value &= value - 1 {
+		res = append(res, bits.TrailingZeros64(value))
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+package fuzzymatcher
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func customer(name, city string) Record {
+	return Record{Fields: []Field{{Name: "name", Value: name}, {Name: "city", Value: city}}}
+}
+
+func TestLink(t *testing.T) {
+	customers := []Record{
+		customer("Jonathan Smith", "Amsterdam"),
+		customer("Maria Garcia", "Rotterdam"),
+		customer("Pieter de Vries", "Utrecht"),
+	}
+	partner := []Record{
+		customer("Garcia, Maria", "Roterdam"),
+		customer("Jonathon Smith", "Amsterdam"),
+		customer("Jane Smith", "Amsterdam"),
+		customer("", "Utrecht"),
+	}
+
+	pairs := Link(customers, partner, LinkOptions{})
+	a.Equal(t, []LinkPair{
+		{Left: 0, Right: 1, Score: 1, Fields: []LinkField{
+			{Name: "name", Similarity: 1, LeftMatched: []int{0, 1}, RightMatched: []int{0, 1}},
+			{Name: "city", Similarity: 1, LeftMatched: []int{0}, RightMatched: []int{0}},
+		}},
+		{Left: 0, Right: 2, Score: 0.75, Fields: []LinkField{
+			{Name: "name", Similarity: 0.5, LeftMatched: []int{1}, RightMatched: []int{1}},
+			{Name: "city", Similarity: 1, LeftMatched: []int{0}, RightMatched: []int{0}},
+		}},
+		{Left: 1, Right: 0, Score: 1, Fields: []LinkField{
+			{Name: "name", Similarity: 1, LeftMatched: []int{0, 1}, RightMatched: []int{0, 1}},
+			{Name: "city", Similarity: 1, LeftMatched: []int{0}, RightMatched: []int{0}},
+		}},
+	}, pairs)
+
+	pairs = Link(customers, partner, LinkOptions{Threshold: 0.5})
+	a.Len(t, pairs, 4, "a matching city is enough with a lower threshold")
+	a.Equal(t, []int{}, pairs[3].Fields[0].LeftMatched)
+
+	pairs = Link(customers, partner, LinkOptions{MaxCandidates: 1, Engine: EngineTrie})
+	a.Len(t, pairs, 2)
+	a.Equal(t, 1, pairs[0].Right)
+
+	a.Equal(t, []LinkPair{}, Link(nil, partner, LinkOptions{}))
+	a.Equal(t, []LinkPair{}, Link(customers, nil, LinkOptions{}))
+}
+
+func TestLinkBoostAndRequired(t *testing.T) {
+	left := []Record{{Fields: []Field{
+		{Name: "name", Value: "Maria Garcia", Boost: 3},
+		{Name: "city", Value: "Rotterdam"},
+	}}}
+	right := []Record{customer("Maria Garcia", "Eindhoven")}
+
+	pairs := Link(left, right, LinkOptions{})
+	a.Len(t, pairs, 1)
+	a.Equal(t, 0.75, pairs[0].Score)
+	a.Equal(t, 0.0, pairs[0].Fields[1].Similarity)
+
+	left[0].Fields[1].Required = true
+	a.Len(t, Link(left, right, LinkOptions{}), 0, "the city is required")
+}
+
+func TestLinkWordsAfterMaxWords(t *testing.T) {
+	// zebra is the 65th word of the right value so matching it must not leak into the next left record
+	right := []Record{{Fields: []Field{{Name: "name", Value: strings.Repeat("filler ", MaxWords) + "zebra"}}}}
+	left := []Record{
+		{Fields: []Field{{Name: "name", Value: "qqq zebra"}}},
+		{Fields: []Field{{Name: "name", Value: "filler qqq"}}},
+	}
+
+	pairs := Link(left, right, LinkOptions{Threshold: 0.001})
+	a.Len(t, pairs, 1)
+	a.Equal(t, 1, pairs[0].Left)
+	a.Equal(t, []int{0}, pairs[0].Fields[0].LeftMatched)
+	a.Less(t, pairs[0].Score, 1.0)
+}
+
+func BenchmarkLink(b *testing.B) {
+	sentences := benchmarkSentences(10_000)
+	records := make([]Record, len(sentences))
+	for idx, sentence := range sentences {
+		records[idx] = customer(sentence, "Amsterdam")
+	}
+
+	for i := 0; i < b.N; i++ {
+		Link(records[:1_000], records, LinkOptions{})
+	}
+}
@@ -1,153 +1,282 @@
 package fuzzymatcher
 
 import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"unicode/utf8"
 )
 
+// wordEntry is a distinct word of the matchers vocabulary
+// Every word is only stored once even if it's used by multiple sentences
+// The letters and postings of the word are stored in the Letters and Postings arenas of the matcher
 type wordEntry struct {
-	// wordIdx contains one bit sifted to the left for each word in the sentence
-	// So wordIdx will be 1, 2, 4, 8, 16, 32, ...
-	// This also means the wordIdx can be a maximum of 64 words
-	WordIdx uint64
-	Letters []rune
+	LettersStart  int32
+	PostingsStart int32
+	PostingsEnd   int32
 
-	len               int
-	allowedOffset     int
-	FuzzyFirstLetter  [3]rune
-	FuzzyLettersOrder [][3]rune
+	len           int32
+	allowedOffset int32
+
+	// FirstLetters is the letter set of the letters a input word can start with and LetterSet the letter set of all letters, used by the prefilter
+	FirstLetters uint64
+	LetterSet    uint64
+}
+
+// posting is a word of a sentence
+type posting struct {
+	Sentence int32
+	// Word is the index of the word within the sentence
+	Word int32
 }
 
-func (we *wordEntry) letterAt(idx int) rune {
-	if idx < 0 || idx >= len(we.Letters) {
+// wordIdx returns one bit sifted to the left for the word in the sentence
+// So wordIdx will be 1, 2, 4, 8, 16, 32, ...
+// This also means the wordIdx can be a maximum of 64 words, for later words wordIdx is 0
+func (p posting) wordIdx() uint64 {
+	if p.Word >= MaxWords {
 		return 0
 	}
-	return we.Letters[idx]
+	return 1 << uint(p.Word)
 }
 
-func (we *wordEntry) calculateFuzzyLetterOrder() {
-	we.len = len(we.Letters)
-	if we.len <= 4 {
-		we.allowedOffset = 1
-	} else if we.len <= 7 {
-		we.allowedOffset = 2
-	} else {
-		we.allowedOffset = 3
+// wordAllowedOffset returns the amount of letters that may be skipped or missing in a word of length letters
+func wordAllowedOffset(length int32) int32 {
+	if length <= 4 {
+		return 1
+	} else if length <= 7 {
+		return 2
 	}
+	return 3
+}
 
-	we.FuzzyLettersOrder = [][3]rune{}
-
-	lettersLen := len(we.Letters)
-	possibleNextLetters := [3]rune{}
-	for i := 0; i < lettersLen-1; i++ {
-		possibleNextLetters = [3]rune{we.letterAt(i + 1), we.letterAt(i + 2), we.letterAt(i + 3)}
-		if we.allowedOffset < 3 {
-			possibleNextLetters[2] = 0
-			if we.allowedOffset < 2 {
-				possibleNextLetters[1] = 0
-			}
-		}
-		we.FuzzyLettersOrder = append(we.FuzzyLettersOrder, possibleNextLetters)
+// newWordEntry creates a vocabulary word of which the letters start at lettersStart in the letters arena
+func newWordEntry(letters []rune, lettersStart int) wordEntry {
+	we := wordEntry{
+		LettersStart: int32(lettersStart),
+		len:          int32(len(letters)),
 	}
+	we.allowedOffset = wordAllowedOffset(we.len)
 
-	we.FuzzyFirstLetter = [3]rune{we.letterAt(0)}
-	nextLetter := we.letterAt(1)
-	if nextLetter != utf8.RuneError && we.allowedOffset >= 2 {
-		we.FuzzyFirstLetter[1] = nextLetter
-
-		nextLetter := we.letterAt(2)
-		if nextLetter != utf8.RuneError && we.allowedOffset >= 3 {
-			we.FuzzyFirstLetter[2] = nextLetter
-		}
+	// An input word can start at one of the first allowedOffset letters, skipping the letters before it
+	for _, letter := range letters[:we.allowedOffset] {
+		we.FirstLetters |= letterBit(letter)
+	}
+	for _, letter := range letters {
+		we.LetterSet |= letterBit(letter)
 	}
+	return we
 }
 
 type pathToWord struct {
-	Letter             rune
-	Sentence           int
-	Word               int
-	WordOffset         int
-	MustRemainingChars int
+	Letter rune
+	// Word is the index of the word within the matchers vocabulary
+	Word               int32
+	WordOffset         int32
+	MustRemainingChars int32
 }
 
 type sentenceT struct {
-	Words                []wordEntry
-	IdxInNewMatcherInput int
-
-	// the fields below are generated with the (*sentence).complete() method
-	Paths       []pathToWord
-	IndexSum    uint64
-	SentenceLen int
+	// The vocabulary index of every word of the sentence is stored in SentenceWords[WordsStart:WordsEnd] of the matcher
+	WordsStart           int32
+	WordsEnd             int32
+	IdxInNewMatcherInput int32
+	SentenceLen          int32
+}
 
-	// Used in the matching process
-	MatchIndexSum uint64
+// wordCount returns the amount of words in the sentence
+func (s *sentenceT) wordCount() int {
+	return int(s.WordsEnd - s.WordsStart)
 }
 
-func (s *sentenceT) complete() {
-	s.Paths = []pathToWord{}
-	for wordIdx, word := range s.Words {
-		for offset, letter := range word.FuzzyFirstLetter {
-			if letter == 0 {
-				break
-			}
-			s.Paths = append(s.Paths, pathToWord{
-				Letter:             letter,
-				Sentence:           -1,
-				Word:               wordIdx,
-				WordOffset:         offset,
-				MustRemainingChars: word.len - word.allowedOffset - 1,
-			})
-		}
-		s.IndexSum |= word.WordIdx
-		s.SentenceLen += word.len
-		if wordIdx != len(s.Words)-1 {
-			// Also add a space character for the
-			s.SentenceLen++
-		}
+// indexSum returns the wordIdx of every word of the sentence combined
+func (s *sentenceT) indexSum() uint64 {
+	if s.wordCount() >= MaxWords {
+		return ^uint64(0)
 	}
+	return 1<<uint(s.wordCount()) - 1
 }
 
 // Matcher is used to match sentences
 type Matcher struct {
 	Sentences []sentenceT
+	// Vocabulary contains every distinct word of all sentences
+	Vocabulary []wordEntry
+
+	// Arenas with the data of all sentences and words
+	SentenceWords []int32   // The vocabulary index of the words of every sentence
+	Letters       []rune    // The letters of every vocabulary word
+	Postings      []posting // The postings of every vocabulary word, ordered by sentence
 
 	// the fields below are generated with the (*Matcher).complete() method
+	// Paths is ordered by letter, the paths of a letter are Paths[PathStarts[i]:PathStarts[i+1]]
+	// where i is the letter itself for ascii letters and utf8.RuneSelf + the index within UnicodePathLetters for other letters
 	Paths                []pathToWord
-	HasPathsWithRuneSelf bool                        // basicly tells if there are complex utf8 chars
-	PathByLetterMap      map[rune][]pathToWord       // Use if HasPathsWithRuneSelf == true
-	PathByLetterList     [utf8.RuneSelf][]pathToWord // Use if HasPathsWithRuneSelf == false
+	PathStarts           []int32
+	UnicodePathLetters   []rune      // Sorted
+	HasPathsWithRuneSelf bool        // basicly tells if there are complex utf8 chars
+	PrefilterKeys        uint64      // Letter set of all PrefilterBuckets
+	PrefilterBuckets     [64][]int32 // Sentences by the letter bit of one of their words, see MayMatch
+
+	// Limits guards the matcher against hostile inputs, see Limits for more info
+	Limits Limits
+
+	// TieBreak decides which sentence is returned by Match if multiple sentences complete on the same input word
+	TieBreak TieBreak
+	// Priorities is used by TieBreakPriority, it contains the priority for every sentence of the NewMatcher input
+	Priorities []int
+
+	// Prefilter makes Match and MatchAll skip inputs for which MayMatch returns false
+	Prefilter bool
+
+	// Engine decides how the input words are matched against the words of the sentences
+	Engine   Engine
+	trieOnce sync.Once
+	// trie contains the *trie once it's build by compiledTrie
+	trie atomic.Value
+
+	// states contains unused match states so matching does not allocate
+	states sync.Pool
+
+	// unmap releases the memory mapped index file, only set for matchers created by OpenIndex
+	unmap func() error
+}
 
-	// Zero alloc cache
-	UTF8RuneCreation  []byte
-	InProgressMatches []inProgressMatch
+// sentenceWords returns the vocabulary index of every word of the sentence
+func (m *Matcher) sentenceWords(sentence *sentenceT) []int32 {
+	return m.SentenceWords[sentence.WordsStart:sentence.WordsEnd]
 }
 
-func (m *Matcher) complete() {
-	m.Paths = []pathToWord{}
-	m.PathByLetterMap = map[rune][]pathToWord{}
-	m.PathByLetterList = [utf8.RuneSelf][]pathToWord{}
+// words returns the normalized words of the sentence
+func (m *Matcher) words(sentence *sentenceT) []string {
+	sentenceWords := m.sentenceWords(sentence)
+	res := make([]string, len(sentenceWords))
+	for idx, word := range sentenceWords {
+		res[idx] = string(m.letters(&m.Vocabulary[word]))
+	}
+	return res
+}
 
-	for idx, sentence := range m.Sentences {
-		for _, path := range sentence.Paths {
-			path.Sentence = idx
+// letters returns the letters of a vocabulary word
+func (m *Matcher) letters(word *wordEntry) []rune {
+	return m.Letters[word.LettersStart : word.LettersStart+word.len]
+}
 
-			letter := path.Letter
+// postings returns every sentence word that is this vocabulary word
+func (m *Matcher) postings(word *wordEntry) []posting {
+	return m.Postings[word.PostingsStart:word.PostingsEnd]
+}
 
-			m.Paths = append(m.Paths, path)
-			list, ok := m.PathByLetterMap[letter]
-			// Add the path to a specific paths list or create a new paths list
-			if !ok {
-				m.PathByLetterMap[letter] = []pathToWord{path}
+// paths returns the paths of the words an input word starting with letter can match
+func (m *Matcher) paths(letter rune) []pathToWord {
+	idx := int(letter)
+	if letter >= utf8.RuneSelf {
+		// Binary search the letter
+		low, high := 0, len(m.UnicodePathLetters)
+		for low < high {
+			middle := int(uint(low+high) >> 1)
+			if m.UnicodePathLetters[middle] < letter {
+				low = middle + 1
 			} else {
-				m.PathByLetterMap[letter] = append(list, path)
+				high = middle
 			}
+		}
+		if low == len(m.UnicodePathLetters) || m.UnicodePathLetters[low] != letter {
+			return nil
+		}
+		idx = utf8.RuneSelf + low
+	}
+	return m.Paths[m.PathStarts[idx]:m.PathStarts[idx+1]]
+}
 
-			if letter < utf8.RuneSelf {
-				m.PathByLetterList[letter] = append(m.PathByLetterList[letter], path)
-			} else {
-				m.HasPathsWithRuneSelf = true
+func (m *Matcher) complete() {
+	// Add the postings of every word ordered by sentence
+	postingsPerWord := make([]int32, len(m.Vocabulary))
+	for _, word := range m.SentenceWords {
+		postingsPerWord[word]++
+	}
+	next := int32(0)
+	for idx := range m.Vocabulary {
+		m.Vocabulary[idx].PostingsStart = next
+		m.Vocabulary[idx].PostingsEnd = next
+		next += postingsPerWord[idx]
+	}
+	m.Postings = make([]posting, len(m.SentenceWords))
+	for idx := range m.Sentences {
+		sentence := &m.Sentences[idx]
+		sentence.SentenceLen = 0
+		for wordIdx, word := range m.sentenceWords(sentence) {
+			vocabularyWord := &m.Vocabulary[word]
+			m.Postings[vocabularyWord.PostingsEnd] = posting{
+				Sentence: int32(idx),
+				Word:     int32(wordIdx),
+			}
+			vocabularyWord.PostingsEnd++
+
+			sentence.SentenceLen += vocabularyWord.len
+			if wordIdx != sentence.wordCount()-1 {
+				// Also add a space character for the
+				sentence.SentenceLen++
 			}
 		}
 	}
+
+	// Collect the paths of every word, a word can be started with any of its first allowedOffset letters
+	paths := []pathToWord{}
+	unicodeLetters := map[rune]bool{}
+	for wordIdx := range m.Vocabulary {
+		word := &m.Vocabulary[wordIdx]
+		for offset, letter := range m.letters(word)[:word.allowedOffset] {
+			paths = append(paths, pathToWord{
+				Letter:             letter,
+				Word:               int32(wordIdx),
+				WordOffset:         int32(offset),
+				MustRemainingChars: word.len - word.allowedOffset - 1,
+			})
+			if letter >= utf8.RuneSelf {
+				unicodeLetters[letter] = true
+			}
+		}
+	}
+
+	m.UnicodePathLetters = make([]rune, 0, len(unicodeLetters))
+	for letter := range unicodeLetters {
+		m.UnicodePathLetters = append(m.UnicodePathLetters, letter)
+	}
+	sort.Slice(m.UnicodePathLetters, func(a, b int) bool {
+		return m.UnicodePathLetters[a] < m.UnicodePathLetters[b]
+	})
+	m.HasPathsWithRuneSelf = len(m.UnicodePathLetters) > 0
+
+	// Order the paths by letter, within a letter the paths keep the order of the vocabulary
+	unicodeLetterIdx := make(map[rune]int, len(m.UnicodePathLetters))
+	for idx, letter := range m.UnicodePathLetters {
+		unicodeLetterIdx[letter] = idx
+	}
+	tableIdx := func(letter rune) int {
+		if letter < utf8.RuneSelf {
+			return int(letter)
+		}
+		return utf8.RuneSelf + unicodeLetterIdx[letter]
+	}
+	m.PathStarts = make([]int32, utf8.RuneSelf+len(m.UnicodePathLetters)+1)
+	for _, path := range paths {
+		m.PathStarts[tableIdx(path.Letter)+1]++
+	}
+	for idx := 1; idx < len(m.PathStarts); idx++ {
+		m.PathStarts[idx] += m.PathStarts[idx-1]
+	}
+	m.Paths = make([]pathToWord, len(paths))
+	fill := make([]int32, len(m.PathStarts))
+	copy(fill, m.PathStarts)
+	for _, path := range paths {
+		idx := tableIdx(path.Letter)
+		m.Paths[fill[idx]] = path
+		fill[idx]++
+	}
+
+	m.completePrefilter()
 }
 
 const upperToLowerCaseOffset = 'a' - 'A'
@@ -156,125 +285,439 @@ const upperToLowerCaseOffset = 'a' - 'A'
 // This function takes relatively long to execute so do this once, and use the returned matcher to match it against lots of entries
 func NewMatcher(sentences ...string) *Matcher {
 	res := Matcher{
-		Sentences:         []sentenceT{},
-		UTF8RuneCreation:  []byte{},
-		InProgressMatches: []inProgressMatch{},
+		Sentences:     []sentenceT{},
+		Vocabulary:    []wordEntry{},
+		SentenceWords: []int32{},
+		Letters:       []rune{},
 	}
+	vocabularyIdxByWord := map[string]int32{}
 
 	for sentenceIdx, sentence := range sentences {
+		words, _ := parseSentence(sentence)
+		if len(words) == 0 {
+			continue
+		}
+
 		parsedSentence := sentenceT{
-			Words:                []wordEntry{},
-			IdxInNewMatcherInput: sentenceIdx,
+			WordsStart:           int32(len(res.SentenceWords)),
+			IdxInNewMatcherInput: int32(sentenceIdx),
 		}
+		for _, letters := range words {
+			key := string(letters)
+			vocabularyIdx, ok := vocabularyIdxByWord[key]
+			if !ok {
+				vocabularyIdx = int32(len(res.Vocabulary))
+				vocabularyIdxByWord[key] = vocabularyIdx
 
-		word := wordEntry{WordIdx: 1}
-		commitWord := func() {
-			if len(word.Letters) == 0 {
-				// Just reset the current word
-				word = wordEntry{WordIdx: word.WordIdx}
-			} else {
-				word.calculateFuzzyLetterOrder()
-				parsedSentence.Words = append(parsedSentence.Words, word)
-				word = wordEntry{WordIdx: word.WordIdx << 1}
+				res.Vocabulary = append(res.Vocabulary, newWordEntry(letters, len(res.Letters)))
+				res.Letters = append(res.Letters, letters...)
 			}
+			res.SentenceWords = append(res.SentenceWords, vocabularyIdx)
 		}
+		parsedSentence.WordsEnd = int32(len(res.SentenceWords))
+		res.Sentences = append(res.Sentences, parsedSentence)
+	}
 
-		for _, c := range []rune(sentence) {
-			if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
-				word.Letters = append(word.Letters, c)
-			} else if c >= 'A' && c <= 'Z' {
-				word.Letters = append(word.Letters, c+upperToLowerCaseOffset)
-			} else if c >= utf8.RuneSelf {
-				newC, ok := checkAndCorredUnicodeChar(c)
-				if ok {
-					word.Letters = append(word.Letters, newC)
-				}
+	res.complete()
+	return &res
+}
+
+// parseSentence splits a sentence into the letters of every word
+// Also returns the characters that where ignored as they cannot be matched
+func parseSentence(sentence string) ([][]rune, []rune) {
+	words := [][]rune{}
+	ignoredChars := []rune{}
+
+	var word []rune
+	commitWord := func() {
+		if len(word) > 0 {
+			words = append(words, word)
+			word = nil
+		}
+	}
+
+	for _, c := range []rune(sentence) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			word = append(word, c)
+		} else if c >= 'A' && c <= 'Z' {
+			word = append(word, c+upperToLowerCaseOffset)
+		} else if c >= utf8.RuneSelf {
+			newC, ok := checkAndCorredUnicodeChar(c)
+			if ok {
+				word = append(word, newC)
 			} else {
-				commitWord()
+				ignoredChars = append(ignoredChars, c)
 			}
+		} else {
+			commitWord()
 		}
-		commitWord()
+	}
+	commitWord()
 
-		if len(parsedSentence.Words) == 0 {
-			continue
+	return words, ignoredChars
+}
+
+// WordSpan is the location of a word within a sentence
+type WordSpan struct {
+	// Start and End are byte offsets
+	Start int
+	End   int
+}
+
+// WordSpans returns where the words of a sentence are, the words are split the same way as the input of Match is
+// So the index of a span is the index of the word used by Completion and Explanation
+// Characters that are ignored, like zero bytes and invalid utf8, are part of the span if they are within a word
+// Unlike NewMatcher zero bytes do not separate words
+func WordSpans(sentence string) []WordSpan {
+	res := []WordSpan{}
+	start := -1
+	end := 0
+
+	for i := 0; i < len(sentence); {
+		c := sentence[i]
+		size := 1
+		isLetter := false
+		if c == 0 {
+			// Zero bytes are ignored
+		} else if c < utf8.RuneSelf {
+			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+				isLetter = true
+			} else if start != -1 {
+				res = append(res, WordSpan{Start: start, End: end})
+				start = -1
+			}
+		} else {
+			// Unicode characters that cannot be matched are ignored but do not end the word
+			var r rune
+			r, size = utf8.DecodeRuneInString(sentence[i:])
+			_, isLetter = checkAndCorredUnicodeChar(r)
 		}
 
-		parsedSentence.complete()
-		res.Sentences = append(res.Sentences, parsedSentence)
+		if isLetter {
+			if start == -1 {
+				start = i
+			}
+			end = i + size
+		}
+		i += size
+	}
+	if start != -1 {
+		res = append(res, WordSpan{Start: start, End: end})
 	}
 
-	res.complete()
-	return &res
+	return res
 }
 
 type inProgressMatch struct {
-	PathToWord    pathToWord
-	Word          *wordEntry
-	Sentence      *sentenceT
+	PathToWord pathToWord
+	Word       *wordEntry
+	// Letters and AllowedOffset are copied from the word so they do not have to be looked up for every input letter
+	Letters       []rune
+	AllowedOffset int
 	WordOffset    int
 	SkippedChars  int
 	NoMoreLetters bool
 }
 
-func (e *inProgressMatch) addWordIdxToSentence() int {
-	e.Sentence.MatchIndexSum |= e.Word.WordIdx
-	if e.Sentence.MatchIndexSum == e.Sentence.IndexSum {
-		return e.Sentence.IdxInNewMatcherInput
+// sentenceMatch contains the matching progress of a sentence
+type sentenceMatch struct {
+	IndexSum     uint64
+	SkippedChars int
+	Start        int
+	End          int
+	// Touched is true if the sentence is in the touched list of the match state
+	Touched bool
+}
+
+// matchState contains everything that changes while matching an input
+// The Matcher itself is only read while matching so multiple match states can be used at the same time on one Matcher
+// A matchState may only be used by one goroutine at a time
+type matchState struct {
+	m *Matcher
+
+	// Zero alloc cache
+	Sentences         []sentenceMatch
+	InProgressMatches []inProgressMatch
+	// touched contains the sentences with progress so only those have to be reset
+	touched []int
+
+	// Used by EngineTrie, trie is nil for EnginePaths
+	trie      *trie
+	token     []rune
+	trieRows  []int
+	trieHits  []trieHit
+	trieEntry inProgressMatch
+	// trieWords is the amount of vocabulary words the current input word is compared with, limited by maxInProgressMatches
+	trieWords int
+
+	// State of the current walk so it can be continued with the next chunk of input
+	beginWord bool
+	wordStart int
+	wordEnd   int
+	// wordLetters is the amount of letters of the current input word
+	wordLetters int
+
+	// stopAfterWord can be set by onWord to stop the walk after all matches of the current input word are handled
+	stopAfterWord bool
+
+	// trace is called for every step of every in progress match, used by Explain
+	trace func(kind StepKind, entry *inProgressMatch, letter rune)
+
+	// If prefix is set the last input word is matched as a prefix of the sentence words, used by Complete
+	// prefixWord is true while the matches of the last input word are committed in prefix mode
+	prefix     bool
+	prefixWord bool
+
+	// Guards set by the functions that can return an error
+	// If one of the guards stops the walk err is set
+	ctx                  context.Context
+	maxInProgressMatches int
+	err                  error
+}
+
+func newMatchState(m *Matcher) *matchState {
+	return &matchState{
+		m:                 m,
+		Sentences:         make([]sentenceMatch, len(m.Sentences)),
+		InProgressMatches: []inProgressMatch{},
 	}
-	return -1
+}
+
+// getState returns a unused match state for this matcher, return it using putState when done
+func (m *Matcher) getState() *matchState {
+	state, ok := m.states.Get().(*matchState)
+	if !ok {
+		return newMatchState(m)
+	}
+	return state
+}
+
+func (m *Matcher) putState(state *matchState) {
+	state.ctx = nil
+	state.maxInProgressMatches = 0
+	state.err = nil
+	state.trace = nil
+	state.prefix = false
+	m.states.Put(state)
+}
+
+// progress returns the matching progress of a sentence and makes sure it's reset before the next walk
+func (s *matchState) progress(sentenceIdx int) *sentenceMatch {
+	progress := &s.Sentences[sentenceIdx]
+	if !progress.Touched {
+		progress.Touched = true
+		s.touched = append(s.touched, sentenceIdx)
+	}
+	return progress
+}
+
+// addWordIdxToSentence marks the sentence word of the posting as matched by the entry
+// start and end are the byte offsets of the input word that matched
+// Returns true if this completed the sentence
+func (s *matchState) addWordIdxToSentence(e *inProgressMatch, p posting, start, end int) bool {
+	wordIdx := p.wordIdx()
+	if wordIdx == 0 || s.Sentences[p.Sentence].IndexSum&wordIdx != 0 {
+		// This word was already matched by another entry or is after the MaxWords limit
+		return false
+	}
+	sentence := s.progress(int(p.Sentence))
+	if sentence.IndexSum == 0 || start < sentence.Start {
+		sentence.Start = start
+	}
+	if end > sentence.End {
+		sentence.End = end
+	}
+	sentence.IndexSum |= wordIdx
+	sentence.SkippedChars += e.SkippedChars + len(e.Letters) - 1 - e.WordOffset
+	return sentence.IndexSum == s.m.Sentences[p.Sentence].indexSum()
+}
+
+// result returns the match result of a completely matched sentence
+func (s *matchState) result(sentenceIdx int) Match {
+	sentence := &s.m.Sentences[sentenceIdx]
+	progress := &s.Sentences[sentenceIdx]
+
+	letters := int(sentence.SentenceLen) - sentence.wordCount() + 1
+	score := 1 - float64(progress.SkippedChars)/float64(letters)
+	if score < 0 {
+		score = 0
+	}
+	return Match{
+		Sentence: int(sentence.IdxInNewMatcherInput),
+		Score:    score,
+		Start:    progress.Start,
+		End:      progress.End,
+	}
+}
+
+// Match describes a sentence that matched
+type Match struct {
+	// Sentence is the index of the sentence within the NewMatcher input
+	Sentence int
+	// Score is 1 if all words of the sentence matched exactly and gets lower the more letters had to be skipped
+	Score float64
+	// Start and End are the byte offsets of the part of the input that contains all matched words
+	Start int
+	End   int
 }
 
 // Match matches a sentence to the matchers input
 // Returns the index of the matched sentenced
 // If nothing found returns -1
 func (m *Matcher) Match(sentence string) int {
-	// Reset the matching index sums and zero alloc cache
-	for idx := range m.Sentences {
-		m.Sentences[idx].MatchIndexSum = 0
+	state := m.getState()
+	res := state.first(sentence)
+	m.putState(state)
+	return res
+}
+
+// MatchAll returns every sentence that matches the input in the order they where completed
+func (m *Matcher) MatchAll(sentence string) []Match {
+	state := m.getState()
+	res := state.all(sentence)
+	m.putState(state)
+	return res
+}
+
+// first returns the index of the first sentence that matched or -1
+// If multiple sentences complete on the same input word the matchers TieBreak decides which one is returned
+func (s *matchState) first(sentence string) int {
+	best := s.firstIdx(sentence)
+	if best == -1 {
+		return -1
+	}
+	return int(s.m.Sentences[best].IdxInNewMatcherInput)
+}
+
+// firstIdx is the same as first but returns the index within the matchers sentences
+// The progress of the returned sentence can be read using result until the next walk
+func (s *matchState) firstIdx(sentence string) int {
+	if s.m.Prefilter && !s.m.MayMatch(sentence) {
+		return -1
+	}
+
+	best := -1
+	s.walk(sentence, false, func(entry *inProgressMatch, p posting, start, end int) bool {
+		if !s.addWordIdxToSentence(entry, p, start, end) {
+			return true
+		}
+
+		// The words are not matched in the order of the sentences so also for TieBreakFirstDeclared all sentences completed by this word have to be checked
+		if best == -1 || s.prefer(int(p.Sentence), best) {
+			best = int(p.Sentence)
+		}
+		s.stopAfterWord = true
+		return true
+	})
+	return best
+}
+
+// all returns all sentences that matched
+func (s *matchState) all(sentence string) []Match {
+	res := []Match{}
+	if s.m.Prefilter && !s.m.MayMatch(sentence) {
+		return res
+	}
+
+	s.match(sentence, func(match Match) bool {
+		res = append(res, match)
+		return true
+	})
+	return res
+}
+
+// match runs the input through the matcher and calls onMatch for every sentence that completely matched
+// If onMatch returns false the matching process stops
+func (s *matchState) match(sentence string, onMatch func(Match) bool) {
+	s.walk(sentence, false, s.onWordMatch(onMatch))
+}
+
+// onWordMatch returns a onWord function for walk that calls onMatch for every sentence that completely matched
+func (s *matchState) onWordMatch(onMatch func(Match) bool) func(entry *inProgressMatch, p posting, start, end int) bool {
+	return func(entry *inProgressMatch, p posting, start, end int) bool {
+		if s.addWordIdxToSentence(entry, p, start, end) {
+			return onMatch(s.result(int(p.Sentence)))
+		}
+		return true
 	}
-	m.InProgressMatches = m.InProgressMatches[:0]
+}
+
+// walk runs the input through the matching state machine and calls onWord for every word of a sentence that was found in the input
+// Every vocabulary word is matched once, if it matched onWord is called for every posting of the word
+// start and end are the byte offsets of the input word that matched
+// If allOccurrences is false words of a sentence that are already marked as matched are not reported again
+// If onWord returns false the matching process stops
+func (s *matchState) walk(sentence string, allOccurrences bool, onWord func(entry *inProgressMatch, p posting, start, end int) bool) {
+	s.reset()
+	s.feed(sentence, 0, true, allOccurrences, onWord)
+}
+
+// reset resets the matching index sums and zero alloc cache so a new input can be walked
+func (s *matchState) reset() {
+	for _, idx := range s.touched {
+		s.Sentences[idx] = sentenceMatch{}
+	}
+	s.touched = s.touched[:0]
+	s.InProgressMatches = s.InProgressMatches[:0]
+	s.token = s.token[:0]
+	s.trie = nil
+	if s.m.Engine == EngineTrie && s.trace == nil {
+		s.trie = s.m.compiledTrie()
+	}
+	s.beginWord = true
+	s.wordStart = 0
+	s.wordEnd = 0
+	s.wordLetters = 0
+	s.stopAfterWord = false
+	s.prefixWord = false
+	s.err = nil
+}
 
+// feed continues the walk with the next chunk of input
+// offset is the byte offset of the chunk within the full input
+// The chunk should not end with an incomplete utf8 character unless final is true
+// If final is true the chunk is the last part of the input
+// Returns false if onWord requested to stop matching
+func (s *matchState) feed(sentence string, offset int, final bool, allOccurrences bool, onWord func(entry *inProgressMatch, p posting, start, end int) bool) bool {
 	sentenceLen := len(sentence)
 	var rLetter rune
 
-	beginWord := true
+	beginWord := s.beginWord
+
 	for i := 0; i < sentenceLen; i++ {
+		if s.ctx != nil && i%contextCheckInterval == 0 {
+			s.err = s.ctx.Err()
+			if s.err != nil {
+				s.beginWord = beginWord
+				return false
+			}
+		}
+
 		letter := sentence[i]
 		if letter == 0 {
 			continue
 		}
+		letterStart := i
 
 		if letter >= utf8.RuneSelf {
-			if beginWord && !m.HasPathsWithRuneSelf {
-				// There are not even words starting with this letter, lets skip this one
-				beginWord = false
-				continue
-			}
-			if !beginWord && len(m.InProgressMatches) == 0 {
+			if !beginWord && len(s.InProgressMatches) == 0 && s.trie == nil {
 				// We are matching nothing on the current word, no need to execute heavy instructions
 				continue
 			}
 
-			switch i {
-			case sentenceLen - 1:
-				// This is a invalid character
-				rLetter = utf8.RuneError
-			case sentenceLen - 2:
-				r, size := utf8.DecodeRune(append(m.UTF8RuneCreation[:0], letter, sentence[i+1]))
-				i += size - 1
-				rLetter, _ = checkAndCorredUnicodeChar(r)
-			case sentenceLen - 3:
-				r, size := utf8.DecodeRune(append(m.UTF8RuneCreation[:0], letter, sentence[i+1], sentence[i+2]))
-				i += size - 1
-				rLetter, _ = checkAndCorredUnicodeChar(r)
-			default:
-				r, size := utf8.DecodeRune(append(m.UTF8RuneCreation[:0], letter, sentence[i+1], sentence[i+2], sentence[i+3]))
-				i += size - 1
-				rLetter, _ = checkAndCorredUnicodeChar(r)
-			}
+			// Note that an incomplete character at the end of the input results in a utf8.RuneError
+			r, size := utf8.DecodeRuneInString(sentence[i:])
+			i += size - 1
+			rLetter, _ = checkAndCorredUnicodeChar(r)
 			if rLetter == utf8.RuneError {
 				continue
 			}
+			if beginWord && rLetter >= utf8.RuneSelf && !s.m.HasPathsWithRuneSelf && s.trie == nil {
+				// There are not even words starting with this letter, lets skip this one
+				// Letters like é are checked after decoding as they are matched as the ascii letter e
+				beginWord = false
+				continue
+			}
 		} else {
 			rLetter = rune(sentence[i])
 
@@ -286,105 +729,220 @@ func (m *Matcher) Match(sentence string) int {
 				// go to next word
 
 				// Firstly lets check if there where any matches from the last word
-				for _, entry := range m.InProgressMatches {
-					// Check if we mis the last chars
-					// If so this entry is oke
-					// Makes sure "banan" can match "banana"
-					if len(entry.Word.FuzzyLettersOrder)-entry.WordOffset <= entry.Word.allowedOffset-entry.SkippedChars-1 {
-						res := entry.addWordIdxToSentence()
-						if res != -1 {
-							return res
-						}
-					}
+				if !s.commitInProgressMatches(allOccurrences, onWord) {
+					s.beginWord = beginWord
+					return false
 				}
 
-				// Reset the m.InProgressMatches so we can scan for new words
-				m.InProgressMatches = m.InProgressMatches[:0]
+				// Reset the s.InProgressMatches so we can scan for new words
+				s.InProgressMatches = s.InProgressMatches[:0]
+				s.token = s.token[:0]
+				s.wordLetters = 0
 				beginWord = true
 				continue
 			}
 		}
 
-		if beginWord {
-			var paths []pathToWord
-			if !m.HasPathsWithRuneSelf || rLetter < utf8.RuneSelf {
-				paths = m.PathByLetterList[rLetter]
-			} else {
-				paths = m.PathByLetterMap[rLetter]
-			}
+		s.wordEnd = offset + i + 1
+		s.wordLetters++
 
-			for _, path := range paths {
-				if sentenceLen-i >= path.MustRemainingChars {
-					sentence := &m.Sentences[path.Sentence]
-					word := &sentence.Words[path.Word]
+		if s.trie != nil {
+			// The trie is searched once the whole input word is known
+			if beginWord {
+				s.wordStart = offset + letterStart
+				beginWord = false
+			}
+			if len(s.token) <= s.trie.Depth+2 {
+				s.token = append(s.token, rLetter)
+			}
+			continue
+		}
 
-					if sentence.MatchIndexSum&word.WordIdx != 0 {
-						// This word was earlier already matched
-						continue
+		if beginWord {
+			s.wordStart = offset + letterStart
+
+			for _, path := range s.m.paths(rLetter) {
+				// If this is not the final chunk we do not know how many chars remain
+				// In prefix mode the last input word can be shorter than the sentence word
+				if !final || s.prefix || sentenceLen-i >= int(path.MustRemainingChars) {
+					s.InProgressMatches = append(s.InProgressMatches, s.m.inProgressMatch(path))
+					if s.trace != nil {
+						s.trace(StepStarted, &s.InProgressMatches[len(s.InProgressMatches)-1], rLetter)
 					}
+				}
+			}
 
-					m.InProgressMatches = append(m.InProgressMatches, inProgressMatch{
-						PathToWord:    path,
-						Word:          word,
-						Sentence:      sentence,
-						WordOffset:    path.WordOffset,
-						SkippedChars:  path.WordOffset,
-						NoMoreLetters: word.len == 1,
-					})
+			if s.maxInProgressMatches > 0 && len(s.InProgressMatches) > s.maxInProgressMatches {
+				s.err = &LimitError{
+					Err:   ErrTooManyInProgressMatches,
+					Limit: s.maxInProgressMatches,
+					Value: len(s.InProgressMatches),
 				}
+				s.beginWord = false
+				return false
 			}
 
 			beginWord = false
 			continue
 		}
 
+		dropped := 0
 	outer:
-		for i := len(m.InProgressMatches) - 1; i >= 0; i-- {
-			entry := m.InProgressMatches[i]
+		for i := len(s.InProgressMatches) - 1; i >= 0; i-- {
+			entry := s.InProgressMatches[i]
 			if !entry.NoMoreLetters {
-				for offset, c := range entry.Word.FuzzyLettersOrder[entry.WordOffset] {
-					if c == rLetter {
-						if offset > 0 && offset >= entry.Word.allowedOffset-entry.SkippedChars {
+				// The input letter can match one of the next allowedOffset letters of the word
+				for offset := 0; offset < entry.AllowedOffset; offset++ {
+					letterIdx := entry.WordOffset + 1 + offset
+					if letterIdx >= len(entry.Letters) {
+						break
+					}
+					if entry.Letters[letterIdx] == rLetter {
+						if offset > 0 && offset >= entry.AllowedOffset-entry.SkippedChars {
 							continue
 						}
 
-						entry.WordOffset += offset + 1
+						entry.WordOffset = letterIdx
 						entry.SkippedChars += offset
-						if entry.WordOffset == len(entry.Word.FuzzyLettersOrder) {
+						if entry.WordOffset == len(entry.Letters)-1 {
 							entry.NoMoreLetters = true
 						}
-						m.InProgressMatches[i] = entry
+						s.InProgressMatches[i] = entry
+						if s.trace != nil {
+							s.trace(StepAdvanced, &s.InProgressMatches[i], rLetter)
+						}
 						continue outer
 					}
-
-					if c == 0 {
-						break
-					}
 				}
 			}
 
-			if entry.SkippedChars < entry.Word.allowedOffset {
+			if entry.SkippedChars < entry.AllowedOffset {
 				entry.SkippedChars++
-				m.InProgressMatches[i] = entry
+				s.InProgressMatches[i] = entry
+				if s.trace != nil {
+					s.trace(StepSkipped, &s.InProgressMatches[i], rLetter)
+				}
 			} else {
-				m.InProgressMatches = append(m.InProgressMatches[:i], m.InProgressMatches[i+1:]...)
+				if s.trace != nil {
+					s.trace(StepDropped, &s.InProgressMatches[i], rLetter)
+				}
+				// Mark the entry as dropped, removing it right away would move all entries after it for every dropped entry
+				s.InProgressMatches[i].SkippedChars = entry.AllowedOffset + 1
+				dropped++
+			}
+		}
+
+		if dropped > 0 {
+			kept := 0
+			for _, entry := range s.InProgressMatches {
+				if entry.SkippedChars <= entry.AllowedOffset {
+					s.InProgressMatches[kept] = entry
+					kept++
+				}
 			}
+			s.InProgressMatches = s.InProgressMatches[:kept]
 		}
 	}
 
-	for _, entry := range m.InProgressMatches {
+	s.beginWord = beginWord
+	if final {
+		s.prefixWord = s.prefix
+		return s.commitInProgressMatches(allOccurrences, onWord)
+	}
+	return true
+}
+
+// commitInProgressMatches calls onWord for every posting of the in progress matches that fully matched the last word
+// Returns false if onWord requested to stop matching or if onWord set stopAfterWord
+func (s *matchState) commitInProgressMatches(allOccurrences bool, onWord func(entry *inProgressMatch, p posting, start, end int) bool) bool {
+	if s.trie != nil {
+		if !s.searchTrie() {
+			return false
+		}
+		for _, hit := range s.trieHits {
+			s.trieEntry = s.m.inProgressMatch(pathToWord{Word: int32(hit.Word)})
+			if s.shortPrefix(s.trieEntry.AllowedOffset) && s.trieEntry.Letters[0] != s.token[0] {
+				continue
+			}
+			s.trieEntry.PathToWord.Letter = s.trieEntry.Letters[0]
+			s.trieEntry.WordOffset = len(s.trieEntry.Letters) - 1
+			s.trieEntry.SkippedChars = hit.Edits
+			s.trieEntry.NoMoreLetters = true
+			if !s.fanOut(&s.trieEntry, allOccurrences, onWord) {
+				return false
+			}
+		}
+		return !s.stopAfterWord
+	}
+
+	// The paths of a word are next to each other so if a word matched using multiple paths only the first one has to be reported
+	lastWord := -1
+	for idx := range s.InProgressMatches {
+		entry := &s.InProgressMatches[idx]
 		// Check if we mis the last chars
 		// If so this entry is oke
 		// Makes sure "banan" can match "banana"
-		if len(entry.Word.FuzzyLettersOrder)-entry.WordOffset <= entry.Word.allowedOffset-entry.SkippedChars-1 {
-			res := entry.addWordIdxToSentence()
-			if res != -1 {
-				return res
+		missingChars := len(entry.Letters) - 1 - entry.WordOffset
+		if s.prefixWord {
+			// The input word is a prefix so the missing chars do not matter
+			missingChars = 0
+		}
+		matched := missingChars <= entry.AllowedOffset-entry.SkippedChars-1
+		if s.shortPrefix(entry.AllowedOffset) && entry.PathToWord.WordOffset > 0 {
+			matched = false
+		}
+		if matched {
+			if int(entry.PathToWord.Word) == lastWord {
+				continue
 			}
+			lastWord = int(entry.PathToWord.Word)
+
+			if s.trace != nil {
+				s.trace(StepMatched, entry, 0)
+			}
+			if !s.fanOut(entry, allOccurrences, onWord) {
+				return false
+			}
+		} else if s.trace != nil {
+			s.trace(StepIncomplete, entry, 0)
 		}
 	}
+	return !s.stopAfterWord
+}
+
+// shortPrefix returns true if the input word is a prefix that is not longer than the allowed offset of a word
+// Such a prefix has to start at the first letter of the word, otherwise "a" would complete every word with an a as one of the first letters
+func (s *matchState) shortPrefix(allowedOffset int) bool {
+	return s.prefixWord && s.wordLetters <= allowedOffset
+}
+
+// inProgressMatch returns a new in progress match that starts at the path
+func (m *Matcher) inProgressMatch(path pathToWord) inProgressMatch {
+	word := &m.Vocabulary[path.Word]
+	return inProgressMatch{
+		PathToWord:    path,
+		Word:          word,
+		Letters:       m.letters(word),
+		AllowedOffset: int(word.allowedOffset),
+		WordOffset:    int(path.WordOffset),
+		SkippedChars:  int(path.WordOffset),
+		NoMoreLetters: word.len == 1,
+	}
+}
 
-	return -1
+// fanOut calls onWord for every posting of the word of a matched entry
+// Returns false if onWord requested to stop matching
+func (s *matchState) fanOut(entry *inProgressMatch, allOccurrences bool, onWord func(entry *inProgressMatch, p posting, start, end int) bool) bool {
+	for _, p := range s.m.postings(entry.Word) {
+		if !allOccurrences && s.Sentences[p.Sentence].IndexSum&p.wordIdx() != 0 {
+			// This word was earlier already matched
+			continue
+		}
+		if !onWord(entry, p, s.wordStart, s.wordEnd) {
+			return false
+		}
+	}
+	return true
 }
 
 func checkAndCorredUnicodeChar(c rune) (rune, bool) {
@@ -3,6 +3,7 @@ package fuzzymatcher
 import (
 	"os"
 	"runtime/pprof"
+	"strconv"
 	"testing"
 
 	a "github.com/stretchr/testify/assert"
@@ -34,9 +35,9 @@ func TestNewMatcher(t *testing.T) {
 
 	a.Len(t, m.Sentences, 1)
 	sentence := m.Sentences[0]
-	a.Len(t, sentence.Words, 1)
-	a.Equal(t, uint64(1), sentence.IndexSum)
-	a.Len(t, sentence.Paths, 1)
+	a.Equal(t, 1, sentence.wordCount())
+	a.Equal(t, uint64(1), sentence.indexSum())
+	a.Len(t, m.Vocabulary, 1)
 	a.NotEqual(t, 0, sentence.SentenceLen)
 
 	m = NewMatcher("foo bar   fooBar")
@@ -44,14 +45,26 @@ func TestNewMatcher(t *testing.T) {
 
 	a.Len(t, m.Sentences, 1)
 	sentence = m.Sentences[0]
-	a.Len(t, sentence.Words, 3)
-	a.Equal(t, uint64(1+1<<1+1<<2), sentence.IndexSum)
-	a.Len(t, sentence.Paths, 3+1) // 3 words + 1 extra letter for "fooBar"
+	a.Equal(t, 3, sentence.wordCount())
+	a.Equal(t, uint64(1+1<<1+1<<2), sentence.indexSum())
+	a.Len(t, m.Vocabulary, 3)
 	a.NotEqual(t, 0, sentence.SentenceLen)
 
 	m = NewMatcher("foo", "bar")
 	a.Len(t, m.Paths, 2)
 
+	// Words used by multiple sentences are only stored once
+	m = NewMatcher("foo bar", "bar baz", "Bar")
+	a.Len(t, m.Vocabulary, 3)
+	a.Len(t, m.Paths, 3)
+	a.Equal(t, []int32{1, 2}, m.sentenceWords(&m.Sentences[1]))
+	a.Equal(t, []string{"bar", "baz"}, m.words(&m.Sentences[1]))
+	a.Equal(t, []posting{
+		{Sentence: 0, Word: 1},
+		{Sentence: 1, Word: 0},
+		{Sentence: 2, Word: 0},
+	}, m.postings(&m.Vocabulary[1]))
+
 	NewMatcher("banana", "i like peers", "foo bar  baz", "another entry that is somwhat long")
 	NewMatcher(lordemIpsum)
 }
@@ -74,6 +87,13 @@ func TestSimpleMultiMatch(t *testing.T) {
 	a.Equal(t, 1, m.Match("are the best fruit bananas?"))
 }
 
+func TestSharedWordsMatchFirstDeclared(t *testing.T) {
+	// "banan" is the first vocabulary word but sentence 1 completes on the same input word and is declared earlier
+	m := NewMatcher("banan peer", "banana", "banan")
+	a.Equal(t, 1, m.Match("banana"))
+	a.Equal(t, 0, m.Match("peer banana"))
+}
+
 func TestMatching(t *testing.T) {
 	testCases := []struct {
 		input       string
@@ -104,6 +124,9 @@ func TestMatching(t *testing.T) {
 		{"Metselaar", "slijterij", false},
 		{"fòÓôÕöl", "foooool", true},
 		{"foooool", "fòÓôÕöl", true},
+		{"etage", "étage", true},
+		{"banana", "“banana”", true},
+		{"banana", "ba\x00nana", true},
 	}
 
 	for _, testCase := range testCases {
@@ -140,6 +163,27 @@ func TestMatching(t *testing.T) {
 	}
 }
 
+func TestMatchAll(t *testing.T) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+	)
+
+	a.Equal(t, []Match{}, m.MatchAll("nothing"))
+	a.Equal(t, []Match{{Sentence: 2, Score: 1, Start: 0, End: 6}}, m.MatchAll("banana"))
+
+	matches := m.MatchAll("i love trees and bananas are the best fruit")
+	a.Len(t, matches, 3)
+	a.Equal(t, 0, matches[0].Sentence)
+	a.Equal(t, 2, matches[1].Sentence)
+	a.Less(t, matches[1].Score, 1.0)
+	a.Equal(t, 1, matches[2].Sentence)
+	a.Equal(t, 1.0, matches[2].Score)
+	a.Equal(t, 17, matches[2].Start)
+	a.Equal(t, 43, matches[2].End)
+}
+
 func BenchmarkMatch(b *testing.B) {
 	// With chinese characters in the NewMatcher input
 	// BenchmarkMatch-12    	  703314	      1464 ns/op	      24 B/op	       3 allocs/op
@@ -186,3 +230,57 @@ func BenchmarkMatch(b *testing.B) {
 	f.Close()
 	// the profile can be inspected using: go tool pprof -http localhost:3333 cpu.profile
 }
+
+func BenchmarkMatchDroppedInProgressMatches(b *testing.B) {
+	// Half of the words starting with a b are dropped while the other half keeps matching the input word
+
+	// Removing every dropped in progress match right away, moving all matches after it
+	// BenchmarkMatchDroppedInProgressMatches 	       9	 366722600 ns/op
+
+	// Removing the dropped in progress matches in one pass after every letter
+	// BenchmarkMatchDroppedInProgressMatches 	     439	   2960203 ns/op
+
+	sentences := make([]string, 20_000)
+	for i := range sentences {
+		if i%2 == 0 {
+			sentences[i] = "banana" + strconv.Itoa(i)
+		} else {
+			sentences[i] = "bbbbbbbbbb" + strconv.Itoa(i)
+		}
+	}
+	matcher := NewMatcher(sentences...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.Match("i want a bbbbbbbbbb")
+	}
+}
+
+func BenchmarkMatchSharedWords(b *testing.B) {
+	// Lots of sentences that share the same words like product names
+	sentences := make([]string, 50_000)
+	for i := range sentences {
+		sentences[i] = "pro max phone " + strconv.Itoa(i)
+	}
+	matcher := NewMatcher(sentences...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.Match("i want a pro max phone")
+	}
+}
+
+func TestWordSpans(t *testing.T) {
+	sentence := "Foo, bär  “baz”12"
+	spans := WordSpans(sentence)
+	a.Equal(t, []WordSpan{{0, 3}, {5, 9}, {14, 22}}, spans)
+	a.Equal(t, "baz”12", sentence[spans[2].Start:spans[2].End], "ignored characters within a word are part of it")
+
+	words, _ := parseSentence(sentence)
+	a.Len(t, spans, len(words))
+	a.Equal(t, []WordSpan{}, WordSpans(" ,. "))
+
+	// Like the input of Match zero bytes and invalid utf8 are ignored
+	a.Equal(t, []WordSpan{{0, 7}, {8, 12}}, WordSpans("ba\x00nana ba\xc3n\xc3"))
+	a.Equal(t, tokenize("ba\x00nana ba\xc3n\xc3")[0].Text, "ba\x00nana")
+}
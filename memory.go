@@ -0,0 +1,50 @@
+package fuzzymatcher
+
+import "unsafe"
+
+// MemoryUsage is the amount of bytes used by the parts of a matcher, see (*Matcher).MemoryUsage
+type MemoryUsage struct {
+	Sentences  int
+	Vocabulary int // The vocabulary words and their letters
+	Postings   int
+	Paths      int
+	Prefilter  int
+	// Trie is only set if the trie is build, that is after the first match using EngineTrie
+	Trie  int
+	Total int
+
+	BytesPerSentence float64
+}
+
+// MemoryUsage reports how many bytes are used by the index of the matcher
+// Only the memory allocated for the index is counted, the match states and go's own bookkeeping are not included
+// MemoryUsage never builds the trie of EngineTrie, it's only counted if a match already build it
+func (m *Matcher) MemoryUsage() MemoryUsage {
+	res := MemoryUsage{
+		Sentences: cap(m.Sentences)*int(unsafe.Sizeof(sentenceT{})) +
+			cap(m.SentenceWords)*int(unsafe.Sizeof(int32(0))),
+		Vocabulary: cap(m.Vocabulary)*int(unsafe.Sizeof(wordEntry{})) +
+			cap(m.Letters)*int(unsafe.Sizeof(rune(0))),
+		Postings: cap(m.Postings) * int(unsafe.Sizeof(posting{})),
+		Paths: cap(m.Paths)*int(unsafe.Sizeof(pathToWord{})) +
+			cap(m.PathStarts)*int(unsafe.Sizeof(int32(0))) +
+			cap(m.UnicodePathLetters)*int(unsafe.Sizeof(rune(0))),
+	}
+
+	for _, bucket := range m.PrefilterBuckets {
+		res.Prefilter += cap(bucket) * int(unsafe.Sizeof(int32(0)))
+	}
+
+	if t := m.builtTrie(); t != nil {
+		res.Trie = cap(t.Nodes) * int(unsafe.Sizeof(trieNode{}))
+		for _, node := range t.Nodes {
+			res.Trie += cap(node.Children) * int(unsafe.Sizeof(int(0)))
+		}
+	}
+
+	res.Total = res.Sentences + res.Vocabulary + res.Postings + res.Paths + res.Prefilter + res.Trie
+	if len(m.Sentences) > 0 {
+		res.BytesPerSentence = float64(res.Total) / float64(len(m.Sentences))
+	}
+	return res
+}
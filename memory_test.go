@@ -0,0 +1,38 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	empty := NewMatcher().MemoryUsage()
+	a.Zero(t, empty.Sentences)
+	a.Zero(t, empty.Vocabulary)
+	a.Zero(t, empty.BytesPerSentence)
+
+	m := NewMatcher("foo bar", "bar baz", "Bar")
+	usage := m.MemoryUsage()
+	a.NotZero(t, usage.Sentences)
+	a.NotZero(t, usage.Vocabulary)
+	a.NotZero(t, usage.Postings)
+	a.NotZero(t, usage.Paths)
+	a.NotZero(t, usage.Prefilter)
+	a.Zero(t, usage.Trie)
+	a.Equal(t, usage.Sentences+usage.Vocabulary+usage.Postings+usage.Paths+usage.Prefilter, usage.Total)
+	a.Equal(t, float64(usage.Total)/3, usage.BytesPerSentence)
+
+	m.Engine = EngineTrie
+	a.Equal(t, usage, m.MemoryUsage(), "the trie is not build by MemoryUsage")
+	m.Match("banana")
+	withTrie := m.MemoryUsage()
+	a.NotZero(t, withTrie.Trie)
+	a.Equal(t, usage.Total+withTrie.Trie, withTrie.Total)
+}
+
+func TestMemoryUsageIsCompact(t *testing.T) {
+	usage := NewMatcher(benchmarkSentences(100_000)...).MemoryUsage()
+	// The shared words are only stored once so most of the memory is used by the sentences themselves
+	a.Less(t, usage.BytesPerSentence, 100.0)
+}
@@ -0,0 +1,150 @@
+package fuzzymatcher
+
+import (
+	"math/bits"
+	"unicode/utf8"
+)
+
+// prefilterMaxChecks is the maximum amount of sentences MayMatch checks before it assumes the input can match
+const prefilterMaxChecks = 1024
+
+// letterBit returns the bit of a letter in a letter set
+// Every ascii letter and digit has its own bit, other letters share the remaining bits
+func letterBit(letter rune) uint64 {
+	switch {
+	case letter >= 'a' && letter <= 'z':
+		return 1 << uint64(letter-'a')
+	case letter >= '0' && letter <= '9':
+		return 1 << uint64(26+letter-'0')
+	default:
+		return 1 << (36 + uint64(letter)%28)
+	}
+}
+
+// completePrefilter builds the buckets used by MayMatch
+// Every sentence is added to the buckets of the first letters of its word that starts with the least common letters
+func (m *Matcher) completePrefilter() {
+	m.PrefilterKeys = 0
+	m.PrefilterBuckets = [64][]int32{}
+
+	// wordsByLetter contains how many words can be matched by an input word starting with the letter
+	wordsByLetter := [64]int{}
+	for _, word := range m.Vocabulary {
+		for letters := word.FirstLetters; letters != 0; letters &= letters - 1 {
+			wordsByLetter[bits.TrailingZeros64(letters)] += int(word.PostingsEnd - word.PostingsStart)
+		}
+	}
+
+	for sentenceIdx := range m.Sentences {
+		keyLetters := uint64(0)
+		keyWords := -1
+		for wordIdx, word := range m.sentenceWords(&m.Sentences[sentenceIdx]) {
+			if wordIdx == MaxWords {
+				// Words after MaxWords are not needed to match the sentence
+				break
+			}
+
+			letters := m.Vocabulary[word].FirstLetters
+			words := 0
+			for l := letters; l != 0; l &= l - 1 {
+				words += wordsByLetter[bits.TrailingZeros64(l)]
+			}
+			if keyWords == -1 || words < keyWords {
+				keyLetters = letters
+				keyWords = words
+			}
+		}
+
+		m.PrefilterKeys |= keyLetters
+		for l := keyLetters; l != 0; l &= l - 1 {
+			bit := bits.TrailingZeros64(l)
+			m.PrefilterBuckets[bit] = append(m.PrefilterBuckets[bit], int32(sentenceIdx))
+		}
+	}
+}
+
+// inputLetters returns the letter set of the first letters of all input words and the letter set of all input letters
+func inputLetters(input string) (starts uint64, letters uint64) {
+	beginWord := true
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if c == 0 {
+			continue
+		}
+
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(input[i:])
+			i += size - 1
+			letter, ok := checkAndCorredUnicodeChar(r)
+			if !ok {
+				continue
+			}
+			letters |= letterBit(letter)
+			if beginWord {
+				starts |= letterBit(letter)
+				beginWord = false
+			}
+			continue
+		}
+
+		if c >= 'A' && c <= 'Z' {
+			c += upperToLowerCaseOffset
+		}
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			letters |= letterBit(rune(c))
+			if beginWord {
+				starts |= letterBit(rune(c))
+				beginWord = false
+			}
+		} else {
+			beginWord = true
+		}
+	}
+	return starts, letters
+}
+
+// MayMatch returns false if the input can not match any sentence
+// There are no false negatives, if MayMatch returns false Match always returns -1, this is true for all engines
+// If MayMatch returns true the input might still not match
+// This only looks at which letters are used by the input so it's a lot cheaper than Match
+func (m *Matcher) MayMatch(input string) bool {
+	starts, letters := inputLetters(input)
+	// An input word can only match a sentence word if it starts with one of the first letters of the sentence word
+	if starts&m.PrefilterKeys == 0 {
+		return false
+	}
+
+	checks := 0
+	for l := starts & m.PrefilterKeys; l != 0; l &= l - 1 {
+		for _, sentenceIdx := range m.PrefilterBuckets[bits.TrailingZeros64(l)] {
+			if checks == prefilterMaxChecks {
+				return true
+			}
+			checks++
+
+			if m.sentenceMayMatch(int(sentenceIdx), starts, letters) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sentenceMayMatch returns true if every word of the sentence can be matched by the input
+// A word can only be matched by an input word that starts with one of its first letters
+// and as at most allowedOffset-1 letters of the word can be skipped at most that many different letters can be missing from the input
+func (m *Matcher) sentenceMayMatch(sentenceIdx int, starts uint64, letters uint64) bool {
+	for wordIdx, word := range m.sentenceWords(&m.Sentences[sentenceIdx]) {
+		if wordIdx == MaxWords {
+			break
+		}
+		vocabularyWord := &m.Vocabulary[word]
+		if vocabularyWord.FirstLetters&starts == 0 {
+			return false
+		}
+		if bits.OnesCount64(vocabularyWord.LetterSet&^letters) > int(vocabularyWord.allowedOffset)-1 {
+			return false
+		}
+	}
+	return true
+}
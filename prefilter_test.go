@@ -0,0 +1,102 @@
+package fuzzymatcher
+
+import (
+	"math/rand"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestInputLetters(t *testing.T) {
+	starts, letters := inputLetters("Foo, bar 12 fooBar")
+	a.Equal(t, letterBit('f')|letterBit('b')|letterBit('1'), starts)
+	a.Equal(t, starts|letterBit('o')|letterBit('a')|letterBit('r')|letterBit('2'), letters)
+
+	starts, letters = inputLetters("“öl” ¿a")
+	a.Equal(t, letterBit('o')|letterBit('a'), starts)
+	a.Equal(t, starts|letterBit('l'), letters)
+
+	starts, letters = inputLetters(" ,.!")
+	a.Equal(t, uint64(0), starts)
+	a.Equal(t, uint64(0), letters)
+}
+
+func TestMayMatch(t *testing.T) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+	)
+
+	a.True(t, m.MayMatch("banana"))
+	a.True(t, m.MayMatch("bnaana"))
+	a.True(t, m.MayMatch("i lveo trees"), "the order of the letters is not checked")
+	a.False(t, m.MayMatch("i lake trees"), "the v of love is missing")
+	a.False(t, m.MayMatch("nothing"))
+	a.False(t, m.MayMatch("love trees"), "every word needs an input word starting with one of its first letters")
+	a.False(t, m.MayMatch(""))
+
+	m.Prefilter = true
+	a.Equal(t, -1, m.Match("nothing"))
+	a.Equal(t, 0, m.Match("i love trees"))
+	a.Equal(t, []Match{}, m.MatchAll("love trees"))
+}
+
+func TestPrefilterHasNoFalseNegatives(t *testing.T) {
+	sentences := benchmarkSentences(2_000)
+	m := NewMatcher(sentences...)
+
+	r := rand.New(rand.NewSource(1))
+	mutate := func(input string) string {
+		letters := []byte(input)
+		for i := 0; i < 3; i++ {
+			idx := r.Intn(len(letters))
+			switch r.Intn(3) {
+			case 0:
+				letters = append(letters[:idx], letters[idx+1:]...)
+			case 1:
+				letters[idx] = byte('a' + r.Intn(26))
+			default:
+				letters = append(letters[:idx], append([]byte{byte('a' + r.Intn(26))}, letters[idx:]...)...)
+			}
+		}
+		return string(letters)
+	}
+
+	for _, engine := range []Engine{EnginePaths, EngineTrie} {
+		m.Engine = engine
+		matched := 0
+		for i := 0; i < 2_000; i++ {
+			input := mutate(sentences[r.Intn(len(sentences))])
+			if m.Match(input) != -1 {
+				matched++
+				a.True(t, m.MayMatch(input), input)
+			}
+		}
+		a.NotZero(t, matched)
+	}
+}
+
+func BenchmarkPrefilter(b *testing.B) {
+	m := NewMatcher(benchmarkSentences(1_000)...)
+	inputs := []string{
+		"nothing to see here",
+		"order 12345 has shipped",
+		"hello world",
+	}
+
+	for _, prefilter := range []bool{false, true} {
+		m.Prefilter = prefilter
+		name := "without"
+		if prefilter {
+			name = "with"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, input := range inputs {
+					m.Match(input)
+				}
+			}
+		})
+	}
+}
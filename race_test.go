@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package fuzzymatcher
+
+// raceEnabled is true when the tests are run with the race detector
+// The race detector randomly drops items from a sync.Pool so allocation tests are not reliable
+const raceEnabled = true
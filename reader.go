@@ -0,0 +1,61 @@
+package fuzzymatcher
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+const readerChunkSize = 32 * 1024
+
+// MatchReader matches the full contents of r as if it where one input passed to MatchAll
+// fn is called for every sentence as soon as it completely matched, the Start and End of the match are offsets in the full stream
+// If fn returns false reading stops
+// Returns the first error returned by r except for io.EOF, the data read before the error is matched as if the stream ended there
+func (m *Matcher) MatchReader(r io.Reader, fn func(Match) bool) error {
+	state := m.getState()
+	defer m.putState(state)
+
+	state.reset()
+	onWord := state.onWordMatch(fn)
+
+	buf := make([]byte, readerChunkSize)
+	offset := 0
+	carry := 0
+	for {
+		n, err := r.Read(buf[carry:])
+		chunk := buf[:carry+n]
+
+		if err != nil {
+			// Also on other errors the last word has to be committed so matches completed by it are not lost
+			state.feed(bytesToString(chunk), offset, true, false, onWord)
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		// Keep the bytes of a utf8 character that is split across reads for the next chunk
+		cut := len(chunk) - incompleteUTF8Suffix(chunk)
+		if !state.feed(bytesToString(chunk[:cut]), offset, false, false, onWord) {
+			return nil
+		}
+		offset += cut
+		carry = copy(buf, chunk[cut:])
+	}
+}
+
+// incompleteUTF8Suffix returns the amount of bytes at the end of b that form the start of a utf8 character that is not yet complete
+func incompleteUTF8Suffix(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax+1; i-- {
+		if b[i] < utf8.RuneSelf {
+			return 0
+		}
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return 0
+			}
+			return len(b) - i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package fuzzymatcher
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestMatchReader(t *testing.T) {
+	m := NewMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"coördinator",
+		"banana",
+	)
+
+	input := strings.Repeat("nothing to see here ", 2000) + "do i love the trees? the coördinator said bananas are the best fruit"
+	expected := m.MatchAll(input)
+	a.Len(t, expected, 4)
+
+	matches := []Match{}
+	err := m.MatchReader(strings.NewReader(input), func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	a.NoError(t, err)
+	a.Equal(t, expected, matches)
+
+	// Feed the input byte by byte to make sure words and utf8 characters split across reads still match
+	matches = []Match{}
+	err = m.MatchReader(iotest.OneByteReader(strings.NewReader(input)), func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	a.NoError(t, err)
+	a.Equal(t, expected, matches)
+	a.Equal(t, "coördinator", input[matches[1].Start:matches[1].End])
+
+	// Stop after the first match
+	matches = []Match{}
+	err = m.MatchReader(iotest.HalfReader(strings.NewReader(input)), func(match Match) bool {
+		matches = append(matches, match)
+		return false
+	})
+	a.NoError(t, err)
+	a.Equal(t, expected[:1], matches)
+}
+
+func TestMatchReaderError(t *testing.T) {
+	m := NewMatcher("banana")
+
+	readErr := errors.New("read error")
+	err := m.MatchReader(iotest.ErrReader(readErr), func(match Match) bool {
+		return true
+	})
+	a.Equal(t, readErr, err)
+
+	// The data returned together with the error is still matched
+	matches := []Match{}
+	r := iotest.DataErrReader(io.MultiReader(strings.NewReader("i like banana"), iotest.ErrReader(readErr)))
+	err = m.MatchReader(r, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	a.Equal(t, readErr, err)
+	a.Equal(t, []Match{{Sentence: 0, Score: 1, Start: 7, End: 13}}, matches)
+}
+
+func TestIncompleteUTF8Suffix(t *testing.T) {
+	a.Equal(t, 0, incompleteUTF8Suffix([]byte("foo")))
+	a.Equal(t, 0, incompleteUTF8Suffix([]byte("coö")))
+	a.Equal(t, 1, incompleteUTF8Suffix([]byte("coö")[:3]))
+	a.Equal(t, 2, incompleteUTF8Suffix([]byte("我")[:2]))
+	a.Equal(t, 0, incompleteUTF8Suffix([]byte{}))
+}
@@ -0,0 +1,149 @@
+package fuzzymatcher
+
+import (
+	"sort"
+)
+
+// Field is a named value of a record
+type Field struct {
+	Name  string
+	Value string
+
+	// Boost is multiplied with the score of this field when it matches
+	// Defaults to 1 if left 0
+	Boost float64
+
+	// Required makes the record only match if this field matched
+	Required bool
+}
+
+func (f Field) boost() float64 {
+	if f.Boost == 0 {
+		return 1
+	}
+	return f.Boost
+}
+
+// Record is a collection of fields that are matched as one entry
+// Every field is matched as it's own sentence so the 64 words limit applies per field
+type Record struct {
+	Fields []Field
+}
+
+type recordField struct {
+	Record int
+	Field  int
+}
+
+// RecordMatcher is used to match inputs against records with multiple fields
+type RecordMatcher struct {
+	Records []Record
+	Matcher *Matcher
+
+	// fieldBySentence maps the sentences of the matcher to their record and field
+	fieldBySentence []recordField
+	// requiredFields contains the amount of required fields per record
+	requiredFields []int
+}
+
+// NewRecordMatcher creates a new matcher for records
+// Like NewMatcher this function takes relatively long to execute so do this once
+func NewRecordMatcher(records ...Record) *RecordMatcher {
+	res := RecordMatcher{
+		Records:         records,
+		fieldBySentence: []recordField{},
+		requiredFields:  make([]int, len(records)),
+	}
+
+	sentences := []string{}
+	for recordIdx, record := range records {
+		for fieldIdx, field := range record.Fields {
+			sentences = append(sentences, field.Value)
+			res.fieldBySentence = append(res.fieldBySentence, recordField{
+				Record: recordIdx,
+				Field:  fieldIdx,
+			})
+			if field.Required {
+				res.requiredFields[recordIdx]++
+			}
+		}
+	}
+
+	res.Matcher = NewMatcher(sentences...)
+	return &res
+}
+
+// FieldMatch describes a field of a record that matched
+type FieldMatch struct {
+	// Field is the index of the field within the record
+	Field int
+	Name  string
+	// Score is the match score of the field multiplied by its boost
+	Score float64
+}
+
+// RecordMatch describes a record that matched
+type RecordMatch struct {
+	// Record is the index of the record within the NewRecordMatcher input
+	Record int
+	// Score is the sum of the scores of all matched fields
+	Score  float64
+	Fields []FieldMatch
+}
+
+// Match returns all records with at least one matching field
+// The results are ordered by score where the highest score comes first
+func (rm *RecordMatcher) Match(input string) []RecordMatch {
+	res := []RecordMatch{}
+	resIdxByRecord := map[int]int{}
+
+	for _, match := range rm.Matcher.MatchAll(input) {
+		location := rm.fieldBySentence[match.Sentence]
+		field := rm.Records[location.Record].Fields[location.Field]
+
+		resIdx, ok := resIdxByRecord[location.Record]
+		if !ok {
+			resIdx = len(res)
+			resIdxByRecord[location.Record] = resIdx
+			res = append(res, RecordMatch{Record: location.Record})
+		}
+
+		score := match.Score * field.boost()
+		res[resIdx].Score += score
+		res[resIdx].Fields = append(res[resIdx].Fields, FieldMatch{
+			Field: location.Field,
+			Name:  field.Name,
+			Score: score,
+		})
+	}
+
+	// Remove the records where not all required fields matched
+	filteredRes := res[:0]
+	for _, recordMatch := range res {
+		required := 0
+		for _, fieldMatch := range recordMatch.Fields {
+			if rm.Records[recordMatch.Record].Fields[fieldMatch.Field].Required {
+				required++
+			}
+		}
+		if required == rm.requiredFields[recordMatch.Record] {
+			filteredRes = append(filteredRes, recordMatch)
+		}
+	}
+	res = filteredRes
+
+	for _, recordMatch := range res {
+		fields := recordMatch.Fields
+		sort.Slice(fields, func(a, b int) bool {
+			return fields[a].Field < fields[b].Field
+		})
+	}
+	sort.Slice(res, func(a, b int) bool {
+		if res[a].Score == res[b].Score {
+			return res[a].Record < res[b].Record
+		}
+		return res[a].Score > res[b].Score
+	})
+
+	return res
+}
@@ -0,0 +1,57 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestRecordMatcher(t *testing.T) {
+	matcher := NewRecordMatcher(
+		Record{Fields: []Field{
+			{Name: "title", Value: "banana shake", Boost: 3},
+			{Name: "brand", Value: "fruity"},
+			{Name: "description", Value: "a shake made from apples"},
+		}},
+		Record{Fields: []Field{
+			{Name: "title", Value: "apple juice", Boost: 3},
+			{Name: "brand", Value: "fruity"},
+			{Name: "description", Value: "made from bananas and shake"},
+		}},
+	)
+
+	matches := matcher.Match("banana shake")
+	a.Len(t, matches, 1)
+	a.Equal(t, 0, matches[0].Record)
+	a.Equal(t, []FieldMatch{{Field: 0, Name: "title", Score: 3}}, matches[0].Fields)
+
+	matches = matcher.Match("a shake made from bananas and apples by fruity")
+	a.Len(t, matches, 2)
+	a.Equal(t, 0, matches[0].Record)
+	a.Equal(t, []string{"title", "brand", "description"}, fieldNames(matches[0].Fields))
+	a.Equal(t, 1, matches[1].Record)
+	a.Equal(t, []string{"brand", "description"}, fieldNames(matches[1].Fields))
+
+	a.Len(t, matcher.Match("pineapple"), 0)
+}
+
+func TestRecordMatcherRequiredField(t *testing.T) {
+	matcher := NewRecordMatcher(
+		Record{Fields: []Field{
+			{Name: "name", Value: "john doe"},
+			{Name: "city", Value: "amsterdam", Required: true},
+		}},
+	)
+
+	a.Len(t, matcher.Match("john doe"), 0)
+	a.Len(t, matcher.Match("amsterdam"), 1)
+	a.Len(t, matcher.Match("john doe from amsterdam")[0].Fields, 2)
+}
+
+func fieldNames(fields []FieldMatch) []string {
+	res := []string{}
+	for _, field := range fields {
+		res = append(res, field.Name)
+	}
+	return res
+}
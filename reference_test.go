@@ -0,0 +1,285 @@
+package fuzzymatcher
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+// refInputWords splits a input into words like the matcher does while matching
+// Unlike NewMatcher zero bytes are ignored instead of separating words
+func refInputWords(input string) [][]rune {
+	words := [][]rune{}
+	word := []rune{}
+	for _, c := range input {
+		switch {
+		case c == 0:
+			// Ignored
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			word = append(word, c)
+		case c >= 'A' && c <= 'Z':
+			word = append(word, c+upperToLowerCaseOffset)
+		case c < utf8.RuneSelf:
+			if len(word) > 0 {
+				words = append(words, word)
+				word = []rune{}
+			}
+		default:
+			// Invalid utf8 is decoded as utf8.RuneError which is ignored
+			if normalized, ok := checkAndCorredUnicodeChar(c); ok {
+				word = append(word, normalized)
+			}
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+	return words
+}
+
+// refWordMatches tells if a input word matches a word of a sentence using the rules described at EngineTrie
+// The first letter of the input word has to be one of the letters of the word and at most allowedOffset-1 letters may be added or removed
+// to turn the input word into the word, the letters of the word before the first input letter count as removed
+//
+// Known differences with EnginePaths (see refPathsWordMatches), EngineTrie has none:
+//   - EnginePaths lines up every input letter with the first letter of the word it finds and does not try the other ways,
+//     "banaana" does not match "banana" as the second a of the input is lined up with the last a of banana so the n is missing
+func refWordMatches(input, word []rune) bool {
+	maxEdits := int(wordAllowedOffset(int32(len(word)))) - 1
+	for start, c := range word {
+		if start > maxEdits {
+			break
+		}
+		if c != input[0] {
+			continue
+		}
+		rest, restWord := input[1:], word[start+1:]
+		edits := start + len(rest) + len(restWord) - 2*refCommonLetters(rest, restWord)
+		if edits <= maxEdits {
+			return true
+		}
+	}
+	return false
+}
+
+// refCommonLetters returns the length of the longest common subsequence of a and b
+func refCommonLetters(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	row := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				row[j+1] = prev[j] + 1
+			case prev[j+1] > row[j]:
+				row[j+1] = prev[j+1]
+			default:
+				row[j+1] = row[j]
+			}
+		}
+		prev, row = row, prev
+	}
+	return prev[len(b)]
+}
+
+// refPathsWordMatches tells if a input word matches a word of a sentence using the greedy rules of EnginePaths
+// The input word can start at one of the first allowed letters of the word, after that every input letter is lined up with the nearest
+// of the next allowed letters of the word that is the same, if there is none the input letter is skipped
+// Both skipping letters of the word and of the input costs one of the allowed letters, together with the missing letters at the end
+// of the word at most allowedOffset-1 letters can be skipped
+func refPathsWordMatches(input, word []rune) bool {
+	allowed := int(wordAllowedOffset(int32(len(word))))
+
+	for start := 0; start < allowed && start < len(word); start++ {
+		if word[start] != input[0] {
+			continue
+		}
+
+		pos := start
+		skipped := start
+		ok := true
+		for _, c := range input[1:] {
+			advanced := false
+			for offset := 0; offset < allowed && pos+1+offset < len(word); offset++ {
+				if word[pos+1+offset] == c && (offset == 0 || offset < allowed-skipped) {
+					pos += 1 + offset
+					skipped += offset
+					advanced = true
+					break
+				}
+			}
+			if advanced {
+				continue
+			}
+			if skipped == allowed {
+				ok = false
+				break
+			}
+			skipped++
+		}
+
+		// The last letters of the word can be missing
+		if ok && skipped+len(word)-1-pos < allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// refMatch matches the input against the sentences by comparing every input word with every word of every sentence
+// Returns what Match would return and the sentences MatchAll would return in ascending order
+func refMatch(sentences []string, input string, wordMatches func(input, word []rune) bool) (int, []int) {
+	words := make([][][]rune, len(sentences))
+	matched := make([][]bool, len(sentences))
+	for idx, sentence := range sentences {
+		words[idx], _ = parseSentence(sentence)
+		if len(words[idx]) > MaxWords {
+			words[idx] = words[idx][:MaxWords]
+		}
+		matched[idx] = make([]bool, len(words[idx]))
+	}
+
+	first := -1
+	all := []int{}
+	completed := make([]bool, len(sentences))
+	for _, inputWord := range refInputWords(input) {
+		for idx := range sentences {
+			if completed[idx] || len(words[idx]) == 0 {
+				continue
+			}
+
+			complete := true
+			for wordIdx, word := range words[idx] {
+				if !matched[idx][wordIdx] && wordMatches(inputWord, word) {
+					matched[idx][wordIdx] = true
+				}
+				complete = complete && matched[idx][wordIdx]
+			}
+			if complete {
+				completed[idx] = true
+				all = append(all, idx)
+			}
+		}
+
+		// If multiple sentences complete on the same input word the first declared one is returned
+		if first == -1 && len(all) > 0 {
+			first = all[0]
+		}
+	}
+
+	sort.Ints(all)
+	return first, all
+}
+
+// checkLikeReference checks if both engines give the same results as the reference implementation
+func checkLikeReference(t *testing.T, sentences []string, input string) {
+	sentenceIdxs := func(matches []Match) []int {
+		res := []int{}
+		for _, match := range matches {
+			res = append(res, match.Sentence)
+		}
+		sort.Ints(res)
+		return res
+	}
+
+	for _, engine := range []Engine{EnginePaths, EngineTrie} {
+		wordMatches := refWordMatches
+		if engine == EnginePaths {
+			wordMatches = refPathsWordMatches
+		}
+		first, all := refMatch(sentences, input, wordMatches)
+		m := NewMatcher(sentences...)
+		m.Engine = engine
+
+		a.Equal(t, first, m.Match(input), "Match(%q) with sentences %q and engine %d", input, sentences, engine)
+		a.Equal(t, all, sentenceIdxs(m.MatchAll(input)), "MatchAll(%q) with sentences %q and engine %d", input, sentences, engine)
+
+		// Feeding the input byte by byte skips the pruning that requires the length of the input
+		streamed := []Match{}
+		a.NoError(t, m.MatchReader(iotest.OneByteReader(strings.NewReader(input)), func(match Match) bool {
+			streamed = append(streamed, match)
+			return true
+		}))
+		a.Equal(t, all, sentenceIdxs(streamed), "MatchReader(%q) with sentences %q and engine %d", input, sentences, engine)
+	}
+}
+
+func TestRefWordMatches(t *testing.T) {
+	for _, testCase := range []struct {
+		input   string
+		word    string
+		matches bool
+		// paths is false for the known differences of EnginePaths
+		paths bool
+	}{
+		{"banana", "banana", true, true},
+		{"banan", "banana", true, true},
+		{"bananas", "banana", true, true},
+		{"anana", "banana", true, true},
+		{"bnana", "banana", true, true},
+		{"banaana", "banana", true, false},
+		{"bana", "banana", false, false},
+		{"nana", "banana", false, false},
+		{"tree", "tree", true, true},
+		{"tre", "tree", false, false},
+		{"trees", "tree", false, false},
+		{"coordinatr", "coordinator", true, true},
+		{"cordinatr", "coordinator", true, true},
+		{"cordinat", "coordinator", false, false},
+	} {
+		input, word := []rune(testCase.input), []rune(testCase.word)
+		a.Equal(t, testCase.matches, refWordMatches(input, word), "%s %s", testCase.input, testCase.word)
+		a.Equal(t, testCase.paths, refPathsWordMatches(input, word), "%s %s", testCase.input, testCase.word)
+
+		m := NewMatcher(testCase.word)
+		a.Equal(t, testCase.paths, m.Match(testCase.input) == 0, "EnginePaths %s %s", testCase.input, testCase.word)
+		m.Engine = EngineTrie
+		a.Equal(t, testCase.matches, m.Match(testCase.input) == 0, "EngineTrie %s %s", testCase.input, testCase.word)
+	}
+}
+
+func TestMatchLikeReference(t *testing.T) {
+	// Every letter of a small alphabet is used often so a lot of words almost match
+	letters := []string{"a", "b", "n", "e", "A", "é", "ş", " ", " ", ",", "\x00", "\xc3", "“"}
+	randomText := func(r *rand.Rand, maxLen int) string {
+		res := strings.Builder{}
+		for i := r.Intn(maxLen); i > 0; i-- {
+			res.WriteString(letters[r.Intn(len(letters))])
+		}
+		return res.String()
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		sentences := make([]string, 1+r.Intn(4))
+		for idx := range sentences {
+			sentences[idx] = randomText(r, 16)
+		}
+		checkLikeReference(t, sentences, randomText(r, 40))
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func FuzzMatch(f *testing.F) {
+	f.Add("I love trees\nbananas are the best fruit\nbanana\npinappel", "on a sunday afternoon i like to eat a pinapel")
+	f.Add("123 Avenue Road", "123 Avvenue Road, Greenmeadows, Napier 4112")
+	f.Add("coördinator\nfòÓôÕöl", "coordinator foooool")
+	f.Add("etage\nşu", "“étage”")
+	f.Add("banana", "ban\x00ana bananá\xc3")
+	f.Add("abcdefghijkl", "bcdefghij")
+	f.Add("a a a", "a")
+
+	f.Fuzz(func(t *testing.T, sentences string, input string) {
+		if len(sentences) > 1024 || len(input) > 4096 {
+			t.Skip()
+		}
+		checkLikeReference(t, strings.Split(sentences, "\n"), input)
+	})
+}
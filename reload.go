@@ -0,0 +1,219 @@
+package fuzzymatcher
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultReloadInterval is the interval used by ReloadingMatcher if ReloadOptions.Interval is not set
+const DefaultReloadInterval = time.Second
+
+// ReloadOptions changes the behavior of NewReloadingMatcher
+type ReloadOptions struct {
+	// CompileOptions is used to compile the sentences of the file, if the file contains problems the old version is kept
+	CompileOptions CompileOptions
+	// Interval is how often the file is checked for changes, defaults to DefaultReloadInterval
+	Interval time.Duration
+	// OnReload is called from the background goroutine after every reload attempt, err is nil if the new version is used
+	OnReload func(version *MatcherVersion, err error)
+}
+
+// MatcherVersion is a version of the sentences file loaded by a ReloadingMatcher
+type MatcherVersion struct {
+	Matcher *Matcher
+	// Sentences contains the lines of the file without blank lines, the results of Matcher are indexes into Sentences
+	Sentences []string
+	// Version starts at 1 and increases with every successful reload
+	Version int
+	ModTime time.Time
+	Size    int64
+}
+
+// ReloadingMatcher serves a matcher for a file that contains one sentence per line and reloads it when the file changes
+// The file is polled for changes so no external dependencies or platform specific apis are used
+// A new matcher is build in the background and swapped atomically, matches that are in progress keep using the old matcher
+// If the new file cannot be read or compiled the old version keeps being used
+// Replace the file by renaming a new file over it, otherwise a partially written file might be loaded
+type ReloadingMatcher struct {
+	path string
+	opts ReloadOptions
+
+	// current contains the *MatcherVersion that is in use
+	current atomic.Value
+
+	// mu is held while reloading
+	mu      sync.Mutex
+	lastErr error
+	// seenModTime and seenSize describe the last version of the file that was tried, also if it failed
+	seenModTime time.Time
+	seenSize    int64
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewReloadingMatcher loads the file and starts watching it for changes
+// Returns an error if the first version of the file cannot be read or compiled
+// Call Close to stop watching the file
+func NewReloadingMatcher(path string, opts ReloadOptions) (*ReloadingMatcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultReloadInterval
+	}
+	rm := &ReloadingMatcher{
+		path: path,
+		opts: opts,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	version, err := rm.load(1)
+	if err != nil {
+		return nil, err
+	}
+	rm.current.Store(version)
+	rm.seenModTime = version.ModTime
+	rm.seenSize = version.Size
+
+	go rm.watch()
+	return rm, nil
+}
+
+// Current returns the version that is currently in use
+// Use the same version for a whole request so the indexes returned by the matcher refer to its Sentences
+func (rm *ReloadingMatcher) Current() *MatcherVersion {
+	return rm.current.Load().(*MatcherVersion)
+}
+
+// Version returns the version number of the version that is currently in use
+func (rm *ReloadingMatcher) Version() int {
+	return rm.Current().Version
+}
+
+// LastError returns the error of the last reload, it's nil if the last reload succeeded
+func (rm *ReloadingMatcher) LastError() error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return rm.lastErr
+}
+
+// Reload reloads the file immediately even if it did not change
+// Returns the same error as LastError afterwards
+func (rm *ReloadingMatcher) Reload() error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return rm.reload()
+}
+
+// Close stops watching the file, the current version can still be used
+// It's safe to call Close multiple times and from multiple goroutines
+func (rm *ReloadingMatcher) Close() {
+	rm.closeOnce.Do(func() { close(rm.stop) })
+	<-rm.done
+}
+
+func (rm *ReloadingMatcher) watch() {
+	defer close(rm.done)
+
+	ticker := time.NewTicker(rm.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rm.stop:
+			return
+		case <-ticker.C:
+			rm.reloadIfChanged()
+		}
+	}
+}
+
+func (rm *ReloadingMatcher) reloadIfChanged() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	modTime, size := fileVersion(rm.path)
+	if modTime.Equal(rm.seenModTime) && size == rm.seenSize {
+		return
+	}
+	rm.reload()
+}
+
+// reload loads the file and swaps the matcher if it succeeds, mu must be held
+func (rm *ReloadingMatcher) reload() error {
+	version, err := rm.load(rm.Current().Version + 1)
+	if version != nil {
+		rm.seenModTime = version.ModTime
+		rm.seenSize = version.Size
+	} else {
+		// Remember the missing or unreadable file so the error is only reported once
+		rm.seenModTime, rm.seenSize = fileVersion(rm.path)
+	}
+	rm.lastErr = err
+	if err == nil {
+		rm.current.Store(version)
+	} else {
+		version = rm.Current()
+	}
+
+	if rm.opts.OnReload != nil {
+		rm.opts.OnReload(version, err)
+	}
+	return err
+}
+
+// load reads and compiles the file
+// If the file can be read but not compiled the version is returned without a matcher so the file is not retried until it changes
+func (rm *ReloadingMatcher) load(versionNr int) (*MatcherVersion, error) {
+	f, err := os.Open(rm.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, info.Size())
+	_, err = f.ReadAt(data, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	version := &MatcherVersion{
+		Sentences: SplitSentences(string(data)),
+		Version:   versionNr,
+		ModTime:   info.ModTime(),
+		Size:      info.Size(),
+	}
+	version.Matcher, err = Compile(version.Sentences, rm.opts.CompileOptions)
+	if err != nil {
+		return version, err
+	}
+	return version, nil
+}
+
+// fileVersion returns the modification time and size of a file, size is -1 if the file does not exist
+func fileVersion(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
+
+// SplitSentences returns the sentences of a file with one sentence per line
+// Windows line endings are supported and blank lines are skipped as they can't be compiled into a sentence
+func SplitSentences(data string) []string {
+	sentences := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) != "" {
+			sentences = append(sentences, line)
+		}
+	}
+	return sentences
+}
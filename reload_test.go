@@ -0,0 +1,161 @@
+package fuzzymatcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func writeSentencesFile(t *testing.T, path string, content string, modTime time.Time) {
+	// Write the file next to it and rename it so the watcher never sees a partially written file
+	tmp := path + ".tmp"
+	a.NoError(t, os.WriteFile(tmp, []byte(content), 0644))
+	// Some file systems only store the modification time in seconds so make sure every write changes it
+	a.NoError(t, os.Chtimes(tmp, modTime, modTime))
+	a.NoError(t, os.Rename(tmp, path))
+}
+
+func TestSplitSentences(t *testing.T) {
+	a.Equal(t, []string{}, SplitSentences(""))
+	a.Equal(t, []string{"foo"}, SplitSentences("foo"))
+	a.Equal(t, []string{"foo", "bar"}, SplitSentences("foo\r\nbar\n"))
+	a.Equal(t, []string{"foo", "bar"}, SplitSentences("foo\n\n \r\nbar\r\n"))
+}
+
+func TestReloadingMatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sentences.txt")
+	modTime := time.Now().Add(-time.Hour)
+	writeSentencesFile(t, path, "I love trees\nbanana\n", modTime)
+
+	reloads := make(chan error, 10)
+	rm, err := NewReloadingMatcher(path, ReloadOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(version *MatcherVersion, err error) { reloads <- err },
+	})
+	a.NoError(t, err)
+	defer rm.Close()
+
+	current := rm.Current()
+	a.Equal(t, 1, current.Version)
+	a.Equal(t, []string{"I love trees", "banana"}, current.Sentences)
+	a.Equal(t, 1, current.Matcher.Match("a banana"))
+	a.NoError(t, rm.LastError())
+
+	// A valid change is used
+	writeSentencesFile(t, path, "banana\npeer\n", modTime.Add(time.Second))
+	a.NoError(t, <-reloads)
+	a.Equal(t, 2, rm.Version())
+	a.Equal(t, 0, rm.Current().Matcher.Match("a banana"))
+	a.Equal(t, 1, current.Matcher.Match("a banana"), "old versions can still be used")
+
+	// An invalid change keeps the old version
+	writeSentencesFile(t, path, "banana\nbanana\n", modTime.Add(2*time.Second))
+	err = <-reloads
+	a.Error(t, err)
+	a.Equal(t, err, rm.LastError())
+	a.Equal(t, 2, rm.Version())
+	a.Equal(t, 1, rm.Current().Matcher.Match("peer"))
+
+	// The invalid file is not retried until it changes
+	time.Sleep(20 * time.Millisecond)
+	a.Len(t, reloads, 0)
+
+	// A missing file keeps the old version
+	a.NoError(t, os.Remove(path))
+	a.Error(t, <-reloads)
+	a.Equal(t, 2, rm.Version())
+
+	writeSentencesFile(t, path, "apple\n", modTime.Add(3*time.Second))
+	a.NoError(t, <-reloads)
+	a.Equal(t, 3, rm.Version())
+	a.NoError(t, rm.LastError())
+
+	// Reload always reloads
+	a.NoError(t, rm.Reload())
+	a.NoError(t, <-reloads)
+	a.Equal(t, 4, rm.Version())
+}
+
+func TestReloadingMatcherInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewReloadingMatcher(filepath.Join(dir, "does-not-exist"), ReloadOptions{})
+	a.Error(t, err)
+
+	path := filepath.Join(dir, "sentences.txt")
+	writeSentencesFile(t, path, "foo\nfoo", time.Now())
+	_, err = NewReloadingMatcher(path, ReloadOptions{})
+	a.Error(t, err, "duplicate sentences are not allowed")
+
+	// Blank lines are skipped
+	writeSentencesFile(t, path, "foo\n\nbar\n", time.Now())
+	rm, err := NewReloadingMatcher(path, ReloadOptions{})
+	a.NoError(t, err)
+	defer rm.Close()
+	a.Equal(t, []string{"foo", "bar"}, rm.Current().Sentences)
+}
+
+func TestReloadingMatcherConcurrentMatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sentences.txt")
+	modTime := time.Now().Add(-time.Hour)
+	writeSentencesFile(t, path, "I love trees\nbanana\n", modTime)
+
+	rm, err := NewReloadingMatcher(path, ReloadOptions{Interval: time.Millisecond})
+	a.NoError(t, err)
+	defer rm.Close()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				version := rm.Current()
+				idx := version.Matcher.Match("i like bananas")
+				if idx == -1 || version.Sentences[idx] != "banana" {
+					t.Errorf("unexpected match %d in version %d", idx, version.Version)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 6; i++ {
+		if i%2 == 0 {
+			writeSentencesFile(t, path, "banana\n", modTime.Add(time.Duration(i)*time.Second))
+		} else {
+			writeSentencesFile(t, path, "I love trees\nbanana\n", modTime.Add(time.Duration(i)*time.Second))
+		}
+		a.Eventually(t, func() bool { return rm.Version() > i }, time.Second, time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestReloadingMatcherClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sentences.txt")
+	writeSentencesFile(t, path, "banana\n", time.Now())
+	rm, err := NewReloadingMatcher(path, ReloadOptions{Interval: time.Millisecond})
+	a.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rm.Close()
+		}()
+	}
+	wg.Wait()
+	rm.Close()
+	a.Equal(t, 0, rm.Current().Matcher.Match("banana"), "the current version can still be used")
+}
@@ -0,0 +1,82 @@
+package fuzzymatcher
+
+import (
+	"sort"
+)
+
+// Occurrence is a location in the scanned document where a word of a sentence was found
+type Occurrence struct {
+	// Word is the index of the word within the sentence
+	Word int
+	// Start and End are the byte offsets of the matched word within the document
+	Start int
+	End   int
+}
+
+// ScanMatch describes a sentence of which at least one word was found in the scanned document
+type ScanMatch struct {
+	// Sentence is the index of the sentence within the NewMatcher input
+	Sentence int
+	// Complete is true if all words of the sentence where found
+	Complete bool
+	// Count is the amount of times the complete sentence occurs in the document,
+	// this is the lowest amount of occurrences of any of the words of the sentence
+	Count int
+	// Occurrences contains every location of a word of this sentence in the order they where found
+	Occurrences []Occurrence
+}
+
+// Scan searches a (long) document for every word of every sentence in a single pass
+// This is the reverse of Match, instead of returning on the first complete sentence it reports every occurrence
+// The results are ordered by sentence
+func (m *Matcher) Scan(document string) []ScanMatch {
+	state := m.getState()
+	defer m.putState(state)
+
+	res := []ScanMatch{}
+	resIdxBySentence := map[int]int{}
+
+	state.walk(document, true, func(entry *inProgressMatch, p posting, start, end int) bool {
+		resIdx, ok := resIdxBySentence[int(p.Sentence)]
+		if !ok {
+			resIdx = len(res)
+			resIdxBySentence[int(p.Sentence)] = resIdx
+			res = append(res, ScanMatch{Sentence: int(m.Sentences[p.Sentence].IdxInNewMatcherInput)})
+		}
+
+		state.progress(int(p.Sentence)).IndexSum |= p.wordIdx()
+		res[resIdx].Occurrences = append(res[resIdx].Occurrences, Occurrence{
+			Word:  int(p.Word),
+			Start: start,
+			End:   end,
+		})
+		return true
+	})
+
+	for sentenceIdx, resIdx := range resIdxBySentence {
+		sentence := &m.Sentences[sentenceIdx]
+		if state.Sentences[sentenceIdx].IndexSum != sentence.indexSum() {
+			continue
+		}
+
+		countPerWord := make([]int, sentence.wordCount())
+		for _, occurrence := range res[resIdx].Occurrences {
+			countPerWord[occurrence.Word]++
+		}
+		count := countPerWord[0]
+		for _, wordCount := range countPerWord[1:] {
+			if wordCount < count {
+				count = wordCount
+			}
+		}
+
+		res[resIdx].Complete = true
+		res[resIdx].Count = count
+	}
+
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Sentence < res[b].Sentence
+	})
+
+	return res
+}
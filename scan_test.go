@@ -0,0 +1,53 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	m := NewMatcher(
+		"banana",
+		"apple pie",
+		"peer review",
+		"pineapple",
+	)
+
+	document := "A banana a day, an aple pie every sunday and another bananas for dessert. Apple!"
+	matches := m.Scan(document)
+	a.Len(t, matches, 2)
+
+	a.Equal(t, 0, matches[0].Sentence)
+	a.True(t, matches[0].Complete)
+	a.Equal(t, 2, matches[0].Count)
+	a.Equal(t, []Occurrence{{Word: 0, Start: 2, End: 8}, {Word: 0, Start: 53, End: 60}}, matches[0].Occurrences)
+	a.Equal(t, "banana", document[2:8])
+	a.Equal(t, "bananas", document[53:60])
+
+	a.Equal(t, 1, matches[1].Sentence)
+	a.True(t, matches[1].Complete)
+	a.Equal(t, 1, matches[1].Count)
+	a.Len(t, matches[1].Occurrences, 3)
+	a.Equal(t, "Apple", document[matches[1].Occurrences[2].Start:matches[1].Occurrences[2].End])
+
+	matches = m.Scan("please review this")
+	a.Len(t, matches, 1)
+	a.Equal(t, 2, matches[0].Sentence)
+	a.False(t, matches[0].Complete)
+	a.Equal(t, 0, matches[0].Count)
+	a.Equal(t, []Occurrence{{Word: 1, Start: 7, End: 13}}, matches[0].Occurrences)
+
+	a.Len(t, m.Scan("nothing to see here"), 0)
+}
+
+func TestScanUnicode(t *testing.T) {
+	m := NewMatcher("coördinator")
+
+	document := "the coördinator and the coordinator"
+	matches := m.Scan(document)
+	a.Len(t, matches, 1)
+	a.Equal(t, 2, matches[0].Count)
+	a.Equal(t, "coördinator", document[matches[0].Occurrences[0].Start:matches[0].Occurrences[0].End])
+	a.Equal(t, "coordinator", document[matches[0].Occurrences[1].Start:matches[0].Occurrences[1].End])
+}
@@ -0,0 +1,342 @@
+package fuzzymatcher
+
+import (
+	"sort"
+	"sync"
+)
+
+// ShardedMatcher partitions the sentences across multiple matchers that are build and matched concurrently
+// The results are the same as those of a single matcher created with the same sentences
+type ShardedMatcher struct {
+	Shards []*Matcher
+	// Offsets contains for every shard the index of its first sentence within the input
+	Offsets []int
+
+	tieBreak   TieBreak
+	priorities []int
+
+	// wordOrder contains for every shard and every vocabulary word of the shard the position the word has within a single matcher with all sentences,
+	// a single matcher reports the sentences completed by the same input word in the order of its words so MatchAll uses this to merge the shards the same way
+	// They are created on first use of MatchAll, wordOrder for EnginePaths and trieWordOrder for EngineTrie
+	wordOrderOnce     sync.Once
+	wordOrder         [][]int32
+	trieWordOrderOnce sync.Once
+	trieWordOrder     [][]int32
+}
+
+// NewShardedMatcher creates a matcher with the sentences divided over the amount of shards
+// Every shard is created on its own goroutine, if shards is 0 or lower runtime.GOMAXPROCS(0) shards are used
+func NewShardedMatcher(shards int, sentences ...string) *ShardedMatcher {
+	return newShardedMatcher(shards, sentences, CompileOptions{})
+}
+
+// CompileSharded is the same as Compile but creates a ShardedMatcher
+// The sentences are validated as a whole so duplicates in different shards are also found
+func CompileSharded(shards int, sentences []string, opts CompileOptions) (*ShardedMatcher, error) {
+	problems := []Diagnostic{}
+	for _, diagnostic := range Validate(sentences, opts) {
+		if opts.Strict || !diagnostic.IsWarning() {
+			problems = append(problems, diagnostic)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &CompileError{Diagnostics: problems}
+	}
+
+	return newShardedMatcher(shards, sentences, opts), nil
+}
+
+func newShardedMatcher(shards int, sentences []string, opts CompileOptions) *ShardedMatcher {
+	shards = batchWorkers(shards)
+	if shards > len(sentences) {
+		shards = len(sentences)
+	}
+	if shards == 0 {
+		shards = 1
+	}
+
+	res := &ShardedMatcher{
+		Shards:     make([]*Matcher, shards),
+		Offsets:    make([]int, shards),
+		tieBreak:   opts.TieBreak,
+		priorities: opts.Priorities,
+	}
+
+	// The shards contain consecutive sentences so the first declared sentence is also in the first shard
+	size := len(sentences) / shards
+	remaining := len(sentences) % shards
+	offset := 0
+	var wg sync.WaitGroup
+	wg.Add(shards)
+	for shard := 0; shard < shards; shard++ {
+		end := offset + size
+		if shard < remaining {
+			end++
+		}
+		res.Offsets[shard] = offset
+
+		go func(shard, start, end int) {
+			defer wg.Done()
+
+			m := NewMatcher(sentences[start:end]...)
+			m.Limits = opts.Limits
+			m.TieBreak = opts.TieBreak
+			if start < len(opts.Priorities) {
+				priorities := opts.Priorities[start:]
+				if len(priorities) > end-start {
+					priorities = priorities[:end-start]
+				}
+				m.Priorities = priorities
+			}
+			m.Engine = opts.Engine
+			m.Prefilter = opts.Prefilter
+			res.Shards[shard] = m
+		}(shard, offset, end)
+
+		offset = end
+	}
+	wg.Wait()
+
+	return res
+}
+
+// shardCandidate is the first sentence that matched within a shard
+type shardCandidate struct {
+	// Match.Sentence is the index within the input of the sharded matcher
+	Match       Match
+	SentenceLen int
+	Words       int
+}
+
+// Match matches the input against all shards concurrently
+// Returns the same sentence as Match of a single matcher with the same sentences would
+func (sm *ShardedMatcher) Match(sentence string) int {
+	candidates := make([]shardCandidate, len(sm.Shards))
+	found := make([]bool, len(sm.Shards))
+
+	sm.each(func(shardIdx int, shard *Matcher) {
+		state := shard.getState()
+		defer shard.putState(state)
+
+		idx := state.firstIdx(sentence)
+		if idx == -1 {
+			return
+		}
+		match := state.result(idx)
+		match.Sentence += sm.Offsets[shardIdx]
+		candidates[shardIdx] = shardCandidate{
+			Match:       match,
+			SentenceLen: int(shard.Sentences[idx].SentenceLen),
+			Words:       shard.Sentences[idx].wordCount(),
+		}
+		found[shardIdx] = true
+	})
+
+	best := -1
+	for idx := range candidates {
+		if found[idx] && (best == -1 || sm.prefer(candidates[idx], candidates[best])) {
+			best = idx
+		}
+	}
+	if best == -1 {
+		return -1
+	}
+	return candidates[best].Match.Sentence
+}
+
+// shardMatch is a match of MatchAll within a shard
+type shardMatch struct {
+	Match Match
+	// Word is the position within a single matcher of the word that completed the sentence in a single matcher
+	Word int32
+}
+
+// MatchAll returns every sentence that matches the input in the same order as MatchAll of a single matcher with the same sentences
+func (sm *ShardedMatcher) MatchAll(sentence string) []Match {
+	matchesByShard := make([][]shardMatch, len(sm.Shards))
+	sm.each(func(shardIdx int, shard *Matcher) {
+		if shard.Prefilter && !shard.MayMatch(sentence) {
+			return
+		}
+		wordOrder := sm.shardWordOrder(shardIdx)
+
+		state := shard.getState()
+		defer shard.putState(state)
+
+		// A single matcher handles the words matched by a input word in its own order so the sentence is completed by the word
+		// that comes last in that order of the words that are newly matched by the input word, not by the word that completed it in this shard
+		lastWord := map[int32]int32{}
+		lastWordEnd := -1
+		matches := []shardMatch{}
+		state.walk(sentence, false, func(entry *inProgressMatch, p posting, start, end int) bool {
+			if end != lastWordEnd {
+				lastWordEnd = end
+				for sentenceIdx := range lastWord {
+					delete(lastWord, sentenceIdx)
+				}
+			}
+			if p.wordIdx() == 0 || state.Sentences[p.Sentence].IndexSum&p.wordIdx() != 0 {
+				return true
+			}
+			if word := wordOrder[entry.PathToWord.Word]; word > lastWord[p.Sentence] {
+				lastWord[p.Sentence] = word
+			}
+
+			if state.addWordIdxToSentence(entry, p, start, end) {
+				match := state.result(int(p.Sentence))
+				match.Sentence += sm.Offsets[shardIdx]
+				matches = append(matches, shardMatch{Match: match, Word: lastWord[p.Sentence]})
+			}
+			return true
+		})
+		matchesByShard[shardIdx] = matches
+	})
+
+	// A single matcher reports the matches per input word, within a input word per vocabulary word and then per sentence
+	// The input word that completed a sentence is the last matched word so it ends at End
+	merged := []shardMatch{}
+	for _, matches := range matchesByShard {
+		merged = append(merged, matches...)
+	}
+	sort.Slice(merged, func(a, b int) bool {
+		if merged[a].Match.End != merged[b].Match.End {
+			return merged[a].Match.End < merged[b].Match.End
+		}
+		if merged[a].Word != merged[b].Word {
+			return merged[a].Word < merged[b].Word
+		}
+		return merged[a].Match.Sentence < merged[b].Match.Sentence
+	})
+
+	res := make([]Match, len(merged))
+	for idx, match := range merged {
+		res[idx] = match.Match
+	}
+	return res
+}
+
+// shardWordOrder returns the position of every vocabulary word of a shard within a single matcher for the engine of the shard
+func (sm *ShardedMatcher) shardWordOrder(shardIdx int) []int32 {
+	if sm.Shards[shardIdx].Engine == EngineTrie {
+		sm.trieWordOrderOnce.Do(func() {
+			sm.trieWordOrder = sm.vocabularyOrder(true)
+		})
+		return sm.trieWordOrder[shardIdx]
+	}
+	sm.wordOrderOnce.Do(func() {
+		sm.wordOrder = sm.vocabularyOrder(false)
+	})
+	return sm.wordOrder[shardIdx]
+}
+
+// vocabularyOrder returns for every shard and vocabulary word of the shard its position in the vocabulary of a single matcher
+// The vocabulary of a single matcher contains the words in the order they first appear in the sentences, so it's the vocabularies of the shards without duplicates
+// If forTrie is set it returns the position in which EngineTrie visits the words instead, that is the order of a depth first walk over the trie of that vocabulary
+func (sm *ShardedMatcher) vocabularyOrder(forTrie bool) [][]int32 {
+	globalIdxByWord := map[string]int32{}
+	globalWords := [][]rune{}
+	res := make([][]int32, len(sm.Shards))
+	for shardIdx, shard := range sm.Shards {
+		res[shardIdx] = make([]int32, len(shard.Vocabulary))
+		for wordIdx := range shard.Vocabulary {
+			letters := shard.letters(&shard.Vocabulary[wordIdx])
+			globalIdx, ok := globalIdxByWord[string(letters)]
+			if !ok {
+				globalIdx = int32(len(globalWords))
+				globalIdxByWord[string(letters)] = globalIdx
+				globalWords = append(globalWords, letters)
+			}
+			res[shardIdx][wordIdx] = globalIdx
+		}
+	}
+	if !forTrie {
+		return res
+	}
+
+	// The children of a trie node are ordered by the first word that contains them like newTrie does
+	t := &trie{Nodes: []trieNode{{Word: -1}}}
+	for globalIdx, letters := range globalWords {
+		nodeIdx := 0
+		for _, letter := range letters {
+			nodeIdx = t.child(nodeIdx, letter)
+		}
+		t.Nodes[nodeIdx].Word = globalIdx
+	}
+	trieOrder := make([]int32, len(globalWords))
+	next := int32(0)
+	var walk func(nodeIdx int)
+	walk = func(nodeIdx int) {
+		if word := t.Nodes[nodeIdx].Word; word >= 0 {
+			trieOrder[word] = next
+			next++
+		}
+		for _, childIdx := range t.Nodes[nodeIdx].Children {
+			walk(childIdx)
+		}
+	}
+	walk(0)
+
+	for _, order := range res {
+		for wordIdx, globalIdx := range order {
+			order[wordIdx] = trieOrder[globalIdx]
+		}
+	}
+	return res
+}
+
+// each calls fn for every shard on its own goroutine and waits for all of them
+func (sm *ShardedMatcher) each(fn func(shardIdx int, shard *Matcher)) {
+	if len(sm.Shards) == 1 {
+		fn(0, sm.Shards[0])
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(sm.Shards))
+	for idx, shard := range sm.Shards {
+		go func(idx int, shard *Matcher) {
+			defer wg.Done()
+			fn(idx, shard)
+		}(idx, shard)
+	}
+	wg.Wait()
+}
+
+// prefer returns true if candidate a should be returned instead of candidate b
+// The sentence that completed on the earliest input word always wins, after that the same rules as (*matchState).prefer apply
+func (sm *ShardedMatcher) prefer(a, b shardCandidate) bool {
+	// A sentence completes on its last matched word so End is the end of the input word that completed it
+	if a.Match.End != b.Match.End {
+		return a.Match.End < b.Match.End
+	}
+
+	switch sm.tieBreak {
+	case TieBreakLongest:
+		if a.SentenceLen != b.SentenceLen {
+			return a.SentenceLen > b.SentenceLen
+		}
+	case TieBreakMostWords:
+		if a.Words != b.Words {
+			return a.Words > b.Words
+		}
+	case TieBreakBestScore:
+		if a.Match.Score != b.Match.Score {
+			return a.Match.Score > b.Match.Score
+		}
+	case TieBreakPriority:
+		priorityA := sm.priority(a.Match.Sentence)
+		priorityB := sm.priority(b.Match.Sentence)
+		if priorityA != priorityB {
+			return priorityA > priorityB
+		}
+	}
+
+	return a.Match.Sentence < b.Match.Sentence
+}
+
+func (sm *ShardedMatcher) priority(sentenceIdx int) int {
+	if sentenceIdx >= len(sm.priorities) {
+		return 0
+	}
+	return sm.priorities[sentenceIdx]
+}
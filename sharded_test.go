@@ -0,0 +1,170 @@
+package fuzzymatcher
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestNewShardedMatcher(t *testing.T) {
+	sm := NewShardedMatcher(3, "a", "b", "c", "d", "e", "f", "g")
+	a.Len(t, sm.Shards, 3)
+	a.Equal(t, []int{0, 3, 5}, sm.Offsets)
+	a.Len(t, sm.Shards[0].Sentences, 3)
+	a.Len(t, sm.Shards[2].Sentences, 2)
+
+	sm = NewShardedMatcher(10, "a", "b")
+	a.Len(t, sm.Shards, 2)
+
+	sm = NewShardedMatcher(4)
+	a.Len(t, sm.Shards, 1)
+	a.Equal(t, -1, sm.Match("a"))
+}
+
+func TestShardedMatcherTieBreak(t *testing.T) {
+	sentences := []string{"banana split", "bananas", "banana", "split"}
+
+	sm := NewShardedMatcher(2, sentences...)
+	a.Equal(t, 1, sm.Match("banana split"))
+	a.Equal(t, 3, sm.Match("split banana"), "the sentence that completes on the earliest input word wins")
+
+	for _, testCase := range []struct {
+		tieBreak   TieBreak
+		priorities []int
+		expected   int
+	}{
+		{TieBreakFirstDeclared, nil, 1},
+		{TieBreakLongest, nil, 1},
+		{TieBreakBestScore, nil, 2},
+		{TieBreakPriority, []int{0, 0, 5, 0}, 2},
+	} {
+		opts := CompileOptions{TieBreak: testCase.tieBreak, Priorities: testCase.priorities}
+		sm, err := CompileSharded(2, sentences, opts)
+		a.NoError(t, err)
+		single, err := Compile(sentences, opts)
+		a.NoError(t, err)
+
+		a.Equal(t, testCase.expected, sm.Match("banana split"), testCase.tieBreak)
+		a.Equal(t, single.Match("banana split"), sm.Match("banana split"), testCase.tieBreak)
+	}
+
+	_, err := CompileSharded(2, []string{"foo", "bar", "foo"}, CompileOptions{})
+	a.Error(t, err)
+}
+
+func TestShardedMatcherMatchesLikeSingleMatcher(t *testing.T) {
+	sentences := benchmarkSentences(1_000)
+	single := NewMatcher(sentences...)
+	sm := NewShardedMatcher(4, sentences...)
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		// Combine words of multiple sentences so matches can be found in multiple shards
+		words := []string{}
+		for j := 0; j < 4; j++ {
+			sentenceWords := strings.Fields(sentences[r.Intn(len(sentences))])
+			words = append(words, sentenceWords[:1+r.Intn(len(sentenceWords))]...)
+		}
+		input := strings.Join(words, " ")
+
+		a.Equal(t, single.Match(input), sm.Match(input), input)
+
+		for _, engine := range []Engine{EnginePaths, EngineTrie} {
+			single.Engine = engine
+			for _, shard := range sm.Shards {
+				shard.Engine = engine
+			}
+			a.Equal(t, single.MatchAll(input), sm.MatchAll(input), input)
+		}
+		single.Engine = EnginePaths
+		for _, shard := range sm.Shards {
+			shard.Engine = EnginePaths
+		}
+	}
+}
+
+func TestShardedMatcherMatchAllOrder(t *testing.T) {
+	// banana completes the second sentence before bananas completes the first one
+	sm := NewShardedMatcher(2, "banana bananas", "banana")
+	a.Equal(t, []int{1, 0}, matchSentences(sm.MatchAll("banana")))
+	a.Equal(t, matchSentences(NewMatcher("banana bananas", "banana").MatchAll("banana")), matchSentences(sm.MatchAll("banana")))
+
+	// Sentences use variants of a few words so a input word matches multiple words that complete sentences in every shard
+	r := rand.New(rand.NewSource(1))
+	pool := []string{}
+	for i := 0; i < 4; i++ {
+		word := ""
+		for j := 5 + r.Intn(3); j > 0; j-- {
+			word += []string{"a", "b", "n", "é"}[r.Intn(4)]
+		}
+		pool = append(pool, word)
+	}
+	variant := func() string {
+		word := pool[r.Intn(len(pool))]
+		switch r.Intn(3) {
+		case 0:
+			return word + "s"
+		case 1:
+			return word[:len(word)-1]
+		default:
+			return word
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		sentences := make([]string, 5+r.Intn(20))
+		for idx := range sentences {
+			sentences[idx] = variant()
+			if r.Intn(2) == 0 {
+				sentences[idx] += " " + variant()
+			}
+		}
+		input := pool[r.Intn(len(pool))] + " " + variant()
+
+		for _, engine := range []Engine{EnginePaths, EngineTrie} {
+			single := NewMatcher(sentences...)
+			single.Engine = engine
+			sm := newShardedMatcher(3, sentences, CompileOptions{Engine: engine})
+			a.Equal(t, single.MatchAll(input), sm.MatchAll(input), "%q %q", sentences, input)
+		}
+	}
+}
+
+func matchSentences(matches []Match) []int {
+	res := []int{}
+	for _, match := range matches {
+		res = append(res, match.Sentence)
+	}
+	return res
+}
+
+func BenchmarkShardedMatcher(b *testing.B) {
+	sentences := benchmarkSentences(100_000)
+	input := "i would like to buy a kobaten marvi and a susupe"
+
+	b.Run("build-single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewMatcher(sentences...)
+		}
+	})
+	b.Run("build-sharded", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewShardedMatcher(0, sentences...)
+		}
+	})
+
+	single := NewMatcher(sentences...)
+	sm := NewShardedMatcher(0, sentences...)
+	b.Run("match-single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			single.Match(input)
+		}
+	})
+	b.Run("match-sharded", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sm.Match(input)
+		}
+	})
+}
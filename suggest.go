@@ -0,0 +1,155 @@
+package fuzzymatcher
+
+import (
+	"sort"
+)
+
+// Suggestion is a word of the matchers vocabulary that is close to the word passed to Suggest
+type Suggestion struct {
+	Word string
+	// Distance is the levenshtein distance between the word passed to Suggest and this word
+	Distance int
+	// Frequency is the amount of times this word is used in the sentences
+	Frequency int
+}
+
+// Suggest returns the n words of all sentences that are the closest to word, useful for "did you mean" prompts
+// The word is normalized the same way as the sentences, if it contains multiple words only the first one is used
+// The suggestions are ordered by distance, frequency and finally alphabetically
+// Words that have nothing in common with the input word are never suggested
+// If a match using EngineTrie already build the trie the words are compared using the trie so words that share a prefix share its work
+func (m *Matcher) Suggest(word string, n int) []Suggestion {
+	res := []Suggestion{}
+	parsedWords, _ := parseSentence(word)
+	if len(parsedWords) == 0 || n <= 0 {
+		return res
+	}
+	letters := parsedWords[0]
+	width := len(letters) + 1
+
+	candidates := []suggestCandidate{}
+	if t := m.builtTrie(); t != nil {
+		// Words that share a prefix also share the rows of the prefix
+		rows := make([]int, (t.Depth+1)*width)
+		for j := 0; j < width; j++ {
+			rows[j] = j
+		}
+		candidates = m.suggestTrie(t, 0, 0, letters, rows, candidates)
+	} else {
+		rows := make([]int, 2*width)
+		for idx := range m.Vocabulary {
+			distance := levenshtein(letters, m.letters(&m.Vocabulary[idx]), rows)
+			candidates = m.addSuggestCandidate(candidates, idx, len(letters), distance)
+		}
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].Distance != candidates[b].Distance {
+			return candidates[a].Distance < candidates[b].Distance
+		}
+		if candidates[a].Frequency != candidates[b].Frequency {
+			return candidates[a].Frequency > candidates[b].Frequency
+		}
+		// Comparing the letters orders the words the same as comparing them as strings
+		aLetters := m.letters(&m.Vocabulary[candidates[a].Word])
+		bLetters := m.letters(&m.Vocabulary[candidates[b].Word])
+		for i := 0; i < len(aLetters) && i < len(bLetters); i++ {
+			if aLetters[i] != bLetters[i] {
+				return aLetters[i] < bLetters[i]
+			}
+		}
+		return len(aLetters) < len(bLetters)
+	})
+
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	for _, candidate := range candidates {
+		res = append(res, Suggestion{
+			Word:      string(m.letters(&m.Vocabulary[candidate.Word])),
+			Distance:  candidate.Distance,
+			Frequency: candidate.Frequency,
+		})
+	}
+	return res
+}
+
+// suggestCandidate is a vocabulary word that might be suggested, the word is only converted to a string if it's suggested
+type suggestCandidate struct {
+	Word      int
+	Distance  int
+	Frequency int
+}
+
+// addSuggestCandidate adds a vocabulary word to the candidates unless it has nothing in common with the input word
+func (m *Matcher) addSuggestCandidate(candidates []suggestCandidate, wordIdx int, inputLen int, distance int) []suggestCandidate {
+	vocabularyWord := &m.Vocabulary[wordIdx]
+	maxDistance := inputLen
+	if int(vocabularyWord.len) > maxDistance {
+		maxDistance = int(vocabularyWord.len)
+	}
+	if distance >= maxDistance {
+		// Every letter has to be changed
+		return candidates
+	}
+
+	return append(candidates, suggestCandidate{
+		Word:      wordIdx,
+		Distance:  distance,
+		Frequency: int(vocabularyWord.PostingsEnd - vocabularyWord.PostingsStart),
+	})
+}
+
+// suggestTrie adds the candidates for the words below a node of the trie
+// rows contains a row of the levenshtein matrix for every depth of the trie, the row of the node's parent is already calculated
+func (m *Matcher) suggestTrie(t *trie, nodeIdx int, depth int, letters []rune, rows []int, candidates []suggestCandidate) []suggestCandidate {
+	width := len(letters) + 1
+	node := &t.Nodes[nodeIdx]
+	if depth > 0 {
+		levenshteinRow(letters, node.Letter, rows[(depth-1)*width:depth*width], rows[depth*width:(depth+1)*width])
+	}
+
+	if node.Word >= 0 {
+		candidates = m.addSuggestCandidate(candidates, node.Word, len(letters), rows[depth*width+len(letters)])
+	}
+	for _, childIdx := range node.Children {
+		candidates = m.suggestTrie(t, childIdx, depth+1, letters, rows, candidates)
+	}
+	return candidates
+}
+
+// levenshtein returns the minimum amount of inserted, removed or replaced letters needed to change a into b
+// rows is used to store the rows of the matrix, it must have room for 2*(len(a)+1) values
+func levenshtein(a, b []rune, rows []int) int {
+	width := len(a) + 1
+	previous, current := rows[:width], rows[width:2*width]
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for _, letter := range b {
+		levenshteinRow(a, letter, previous, current)
+		previous, current = current, previous
+	}
+
+	return previous[len(a)]
+}
+
+// levenshteinRow calculates the row of the levenshtein matrix after adding letter to b using the row before it
+func levenshteinRow(a []rune, letter rune, previous, current []int) {
+	current[0] = previous[0] + 1
+	for j := 1; j <= len(a); j++ {
+		cost := 1
+		if a[j-1] == letter {
+			cost = 0
+		}
+
+		current[j] = previous[j-1] + cost
+		if previous[j]+1 < current[j] {
+			current[j] = previous[j] + 1
+		}
+		if current[j-1]+1 < current[j] {
+			current[j] = current[j-1] + 1
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	m := NewMatcher(
+		"banana shake",
+		"banana bread",
+		"bandana",
+		"I love trees",
+		"coördinator",
+	)
+
+	a.Equal(t, []Suggestion{
+		{Word: "banana", Distance: 1, Frequency: 2},
+		{Word: "bandana", Distance: 1, Frequency: 1},
+	}, m.Suggest("Bannana", 2))
+
+	a.Equal(t, []Suggestion{{Word: "coordinator", Distance: 0, Frequency: 1}}, m.Suggest("coordinator", 1))
+	a.Equal(t, "trees", m.Suggest("tres please", 1)[0].Word)
+
+	a.Len(t, m.Suggest("xyz", 10), 0)
+	a.Len(t, m.Suggest("", 10), 0)
+	a.Len(t, m.Suggest("banana", 0), 0)
+}
+
+func TestSuggestWithTrie(t *testing.T) {
+	m := NewMatcher(benchmarkSentences(1_000)...)
+	inputs := []string{"bannana", "trees", "coordinatr", "x", "abcdefghijklmnop"}
+
+	expected := [][]Suggestion{}
+	for _, input := range inputs {
+		expected = append(expected, m.Suggest(input, 10))
+	}
+
+	// Suggest uses the trie once it's build by a match
+	m.Engine = EngineTrie
+	m.Match("banana")
+	a.NotNil(t, m.builtTrie())
+	for idx, input := range inputs {
+		a.Equal(t, expected[idx], m.Suggest(input, 10), input)
+	}
+}
+
+func BenchmarkSuggest(b *testing.B) {
+	m := NewMatcher(benchmarkSentences(10_000)...)
+	b.Run("vocabulary", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.Suggest("bannana", 5)
+		}
+	})
+
+	m.Engine = EngineTrie
+	m.Match("banana")
+	b.Run("trie", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.Suggest("bannana", 5)
+		}
+	})
+}
+
+func TestLevenshtein(t *testing.T) {
+	testCases := []struct {
+		a        string
+		b        string
+		distance int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"banana", "banana", 0},
+		{"banana", "bananas", 1},
+		{"banana", "bnana", 1},
+		{"kitten", "sitting", 3},
+		{"coördinator", "coordinator", 1},
+	}
+
+	for _, testCase := range testCases {
+		a.Equal(t, testCase.distance, levenshtein([]rune(testCase.a), []rune(testCase.b), make([]int, 2*(len([]rune(testCase.a))+1))), "%s %s", testCase.a, testCase.b)
+	}
+}
@@ -0,0 +1,59 @@
+package fuzzymatcher
+
+// TieBreak decides which sentence Match returns if multiple sentences complete on the same input word
+type TieBreak int
+
+const (
+	// TieBreakFirstDeclared returns the sentence that comes first in the NewMatcher input, this is the default
+	TieBreakFirstDeclared TieBreak = iota
+	// TieBreakLongest returns the sentence with the most letters
+	TieBreakLongest
+	// TieBreakMostWords returns the sentence with the most words
+	TieBreakMostWords
+	// TieBreakBestScore returns the sentence with the highest match score
+	TieBreakBestScore
+	// TieBreakPriority returns the sentence with the highest priority in Matcher.Priorities
+	// Sentences without a priority have a priority of 0
+	TieBreakPriority
+)
+
+func (m *Matcher) priority(sentenceIdx int) int {
+	inputIdx := int(m.Sentences[sentenceIdx].IdxInNewMatcherInput)
+	if inputIdx >= len(m.Priorities) {
+		return 0
+	}
+	return m.Priorities[inputIdx]
+}
+
+// prefer returns true if sentence a should be returned instead of sentence b according to the matchers TieBreak
+// Both sentences must be completely matched
+// If the tie break policy considers both sentences equal the first declared sentence is preferred
+func (s *matchState) prefer(a, b int) bool {
+	sentenceA := &s.m.Sentences[a]
+	sentenceB := &s.m.Sentences[b]
+
+	switch s.m.TieBreak {
+	case TieBreakLongest:
+		if sentenceA.SentenceLen != sentenceB.SentenceLen {
+			return sentenceA.SentenceLen > sentenceB.SentenceLen
+		}
+	case TieBreakMostWords:
+		if sentenceA.wordCount() != sentenceB.wordCount() {
+			return sentenceA.wordCount() > sentenceB.wordCount()
+		}
+	case TieBreakBestScore:
+		scoreA := s.result(a).Score
+		scoreB := s.result(b).Score
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+	case TieBreakPriority:
+		priorityA := s.m.priority(a)
+		priorityB := s.m.priority(b)
+		if priorityA != priorityB {
+			return priorityA > priorityB
+		}
+	}
+
+	return sentenceA.IdxInNewMatcherInput < sentenceB.IdxInNewMatcherInput
+}
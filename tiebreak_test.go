@@ -0,0 +1,47 @@
+package fuzzymatcher
+
+import (
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func TestTieBreak(t *testing.T) {
+	m := NewMatcher(
+		"banana",
+		"ripe banana",
+		"bananas",
+		"banana",
+	)
+	input := "ripe bananas"
+
+	testCases := []struct {
+		tieBreak   TieBreak
+		priorities []int
+		expected   int
+	}{
+		{TieBreakFirstDeclared, nil, 0},
+		{TieBreakLongest, nil, 1},
+		{TieBreakMostWords, nil, 1},
+		{TieBreakBestScore, nil, 2},
+		{TieBreakPriority, []int{0, 0, 0, 5}, 3},
+		{TieBreakPriority, []int{1, 2}, 1},
+		{TieBreakPriority, nil, 0},
+	}
+
+	for _, testCase := range testCases {
+		m.TieBreak = testCase.tieBreak
+		m.Priorities = testCase.priorities
+		a.Equal(t, testCase.expected, m.Match(input), "tie break %d", testCase.tieBreak)
+		a.Equal(t, testCase.expected, m.MatchBytes([]byte(input)), "tie break %d", testCase.tieBreak)
+	}
+}
+
+func TestTieBreakOnlyAppliesToTheSameWord(t *testing.T) {
+	m := NewMatcher("banana", "ripe banana")
+	m.TieBreak = TieBreakLongest
+
+	// banana already completes on the first word so the longer sentence is never considered
+	a.Equal(t, 0, m.Match("banana ripe"))
+	a.Equal(t, -1, m.Match("nothing"))
+}
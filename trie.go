@@ -0,0 +1,192 @@
+package fuzzymatcher
+
+// Engine decides how the words of the input are matched against the words of the sentences
+type Engine int
+
+const (
+	// EnginePaths starts a match for every word that begins with the first letter of the input word and advances all of them letter by letter, this is the default
+	EnginePaths Engine = iota
+	// EngineTrie compiles all words into a trie that is walked once per input word while keeping track of the edit distance,
+	// words that share a prefix are only matched once so this scales better with large vocabularies
+	// A word matches if at most allowedOffset-1 letters have to be added or removed and the first letter of the input word is part of the word,
+	// EnginePaths uses the same rules but stops at the first way it finds to line up the letters so EngineTrie can find matches EnginePaths misses
+	// For this engine Limits.MaxInProgressMatches limits the amount of vocabulary words an input word is compared with and Explain always uses EnginePaths
+	EngineTrie
+)
+
+// trieNoMatch is the edit distance used for letters of the input that cannot be matched
+const trieNoMatch = 1 << 20
+
+type trieNode struct {
+	Letter rune
+	// Word is the index of the vocabulary word that ends at this node or -1
+	Word int
+	// MaxEdits is the highest amount of edits allowed by this word or any word below this node
+	MaxEdits int
+	Children []int
+}
+
+type trie struct {
+	// Nodes contains all nodes, the first node is the root
+	Nodes []trieNode
+	// Depth is the amount of letters of the longest word
+	Depth int
+}
+
+func newTrie(m *Matcher) *trie {
+	t := &trie{Nodes: []trieNode{{Word: -1}}}
+
+	for wordIdx := range m.Vocabulary {
+		letters := m.letters(&m.Vocabulary[wordIdx])
+		maxEdits := int(m.Vocabulary[wordIdx].allowedOffset) - 1
+		nodeIdx := 0
+		for _, letter := range letters {
+			if t.Nodes[nodeIdx].MaxEdits < maxEdits {
+				t.Nodes[nodeIdx].MaxEdits = maxEdits
+			}
+			nodeIdx = t.child(nodeIdx, letter)
+		}
+		if t.Nodes[nodeIdx].MaxEdits < maxEdits {
+			t.Nodes[nodeIdx].MaxEdits = maxEdits
+		}
+		t.Nodes[nodeIdx].Word = wordIdx
+
+		if len(letters) > t.Depth {
+			t.Depth = len(letters)
+		}
+	}
+
+	return t
+}
+
+// child returns the child of a node with the letter and creates it if it does not yet exist
+func (t *trie) child(nodeIdx int, letter rune) int {
+	for _, childIdx := range t.Nodes[nodeIdx].Children {
+		if t.Nodes[childIdx].Letter == letter {
+			return childIdx
+		}
+	}
+
+	childIdx := len(t.Nodes)
+	t.Nodes = append(t.Nodes, trieNode{Letter: letter, Word: -1})
+	t.Nodes[nodeIdx].Children = append(t.Nodes[nodeIdx].Children, childIdx)
+	return childIdx
+}
+
+// compiledTrie returns the trie of the matchers vocabulary, it's build on first use
+func (m *Matcher) compiledTrie() *trie {
+	m.trieOnce.Do(func() {
+		m.trie.Store(newTrie(m))
+	})
+	return m.trie.Load().(*trie)
+}
+
+// builtTrie returns the trie if compiledTrie already build it and nil otherwise
+func (m *Matcher) builtTrie() *trie {
+	t, _ := m.trie.Load().(*trie)
+	return t
+}
+
+type trieHit struct {
+	Word  int
+	Edits int
+}
+
+// searchTrie sets trieHits to the vocabulary words that match the current input word
+// Returns false if the input word is compared with more words than allowed by maxInProgressMatches, err is set in that case
+func (s *matchState) searchTrie() bool {
+	s.trieHits = s.trieHits[:0]
+	s.trieWords = 0
+	n := len(s.token)
+	if n == 0 || n > s.trie.Depth+2 {
+		// No word allows more than 2 added letters
+		return true
+	}
+
+	// Every depth of the trie has a row with the edit distance for every amount of input letters,
+	// the rows are the states of the levenshtein automaton of the input word
+	width := n + 1
+	size := (s.trie.Depth + 1) * width
+	if cap(s.trieRows) < size {
+		s.trieRows = make([]int, size)
+	}
+	s.trieRows = s.trieRows[:size]
+
+	// The first input letter must match a letter of the word so no input letters can be added before the word
+	s.trieRows[0] = 0
+	for j := 1; j <= n; j++ {
+		s.trieRows[j] = trieNoMatch
+	}
+
+	for _, childIdx := range s.trie.Nodes[0].Children {
+		if !s.searchTrieNode(childIdx, 1, trieNoMatch) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchTrieNode calculates the row of a node and continues with its children if they can still match
+// bestPrefix is the lowest edit distance of the complete input word to the letters above this node, used in prefix mode
+// Returns false if the search has to stop because of maxInProgressMatches
+func (s *matchState) searchTrieNode(nodeIdx int, depth int, bestPrefix int) bool {
+	node := &s.trie.Nodes[nodeIdx]
+	n := len(s.token)
+	width := n + 1
+	parent := s.trieRows[(depth-1)*width : depth*width]
+	row := s.trieRows[depth*width : (depth+1)*width]
+
+	// Skip the letter of the word
+	row[0] = parent[0] + 1
+	rowMin := row[0]
+	for j := 1; j <= n; j++ {
+		cost := parent[j] + 1
+		if s.token[j-1] == node.Letter && parent[j-1] < cost {
+			cost = parent[j-1]
+		}
+		if j > 1 && row[j-1]+1 < cost {
+			// Skip the letter of the input
+			cost = row[j-1] + 1
+		}
+		row[j] = cost
+		if cost < rowMin {
+			rowMin = cost
+		}
+	}
+
+	if s.prefixWord && row[n] < bestPrefix {
+		bestPrefix = row[n]
+	}
+
+	if node.Word >= 0 {
+		s.trieWords++
+		if s.maxInProgressMatches > 0 && s.trieWords > s.maxInProgressMatches {
+			s.err = &LimitError{
+				Err:   ErrTooManyInProgressMatches,
+				Limit: s.maxInProgressMatches,
+				Value: s.trieWords,
+			}
+			return false
+		}
+
+		edits := row[n]
+		if s.prefixWord {
+			// The input word is a prefix so the missing letters do not matter
+			edits = bestPrefix
+		}
+		if edits <= int(s.m.Vocabulary[node.Word].allowedOffset)-1 {
+			s.trieHits = append(s.trieHits, trieHit{Word: node.Word, Edits: edits})
+		}
+	}
+
+	if rowMin > node.MaxEdits && bestPrefix > node.MaxEdits {
+		// The edit distance only gets higher for the words below this node
+		return true
+	}
+	for _, childIdx := range node.Children {
+		if !s.searchTrieNode(childIdx, depth+1, bestPrefix) {
+			return false
+		}
+	}
+	return true
+}
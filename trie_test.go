@@ -0,0 +1,177 @@
+package fuzzymatcher
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	a "github.com/stretchr/testify/assert"
+)
+
+func newTrieTestMatcher(sentences ...string) *Matcher {
+	m := NewMatcher(sentences...)
+	m.Engine = EngineTrie
+	return m
+}
+
+func TestNewTrie(t *testing.T) {
+	m := NewMatcher("bana banana", "band")
+	tr := newTrie(m)
+
+	// root, b, a, n, a, n, a, d
+	a.Len(t, tr.Nodes, 8)
+	a.Equal(t, 6, tr.Depth)
+	a.Equal(t, 1, tr.Nodes[0].MaxEdits)
+	a.Equal(t, 0, tr.Nodes[4].Word)
+	a.Equal(t, 1, tr.Nodes[6].Word)
+	a.Equal(t, 2, tr.Nodes[7].Word)
+	a.Equal(t, 0, tr.Nodes[7].MaxEdits)
+}
+
+func TestTrieEngineMatchesLikePaths(t *testing.T) {
+	sentences := []string{
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+		"pinappel",
+		"123 Avenue Road",
+		"coördinator",
+		"bananen lekker",
+	}
+	paths := NewMatcher(sentences...)
+	trie := newTrieTestMatcher(sentences...)
+
+	inputs := []string{
+		"nothing",
+		"i love trees",
+		"banana",
+		"bananas are the best fruit",
+		"are the best fruit bananas?",
+		"do you also love trees? i do.",
+		"on a sunday afternoon i like to eat a pinapel",
+		"123 Avvenue Road, Greenmeadows, Napier 4112",
+		"coordinator",
+		"ik vind bananen erg lekker",
+		"bananen zijn vies",
+		"slijterij",
+	}
+	for _, input := range inputs {
+		a.Equal(t, paths.Match(input), trie.Match(input), input)
+		// Sentences completed by the same input word can be in a different order
+		a.ElementsMatch(t, paths.MatchAll(input), trie.MatchAll(input), input)
+	}
+}
+
+func TestTrieEngine(t *testing.T) {
+	m := newTrieTestMatcher("banana", "foo")
+
+	a.Equal(t, 0, m.Match("banaana"), "EnginePaths misses this one")
+	a.Equal(t, 0, m.Match("bananas"))
+	a.Equal(t, 0, m.Match("banan"))
+	a.Equal(t, -1, m.Match("bnaana"))
+	a.Equal(t, 0, m.Match("anana"))
+	a.Equal(t, -1, m.Match("xbanana"), "the first letter must be part of the word")
+	a.Equal(t, 1, m.Match("foo"))
+	a.Equal(t, -1, m.Match("fooo"), "short words must match exactly")
+	a.Equal(t, -1, m.Match(strings.Repeat("a", 100)))
+
+	match := m.MatchAll("a bannana")
+	a.Equal(t, []Match{{Sentence: 0, Score: 1 - 1.0/6, Start: 2, End: 9}}, match)
+}
+
+func TestTrieEngineComplete(t *testing.T) {
+	m := newTrieTestMatcher(
+		"banana shake",
+		"banana bread",
+		"bandana",
+		"I love trees",
+	)
+
+	a.Equal(t, []int{0, 1, 2}, completionSentences(m.Complete("bana", 0)))
+	// "bnan" is "ban" with one added letter so unlike EnginePaths this also completes "bandana"
+	a.Equal(t, []int{0, 1, 2}, completionSentences(m.Complete("bnan", 0)))
+	a.Equal(t, []Completion{{Sentence: 3, Word: 2, MatchedWords: 3, Matched: []int{0, 1, 2}, Complete: true, Score: 1}}, m.Complete("i love tr", 0))
+}
+
+func TestTrieEngineDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	m := newTrieTestMatcher(
+		"I love trees",
+		"bananas are the best fruit",
+		"banana",
+	)
+	inputs := []string{"nothing", "i love trees", "on a sunday afternoon i like to eat a banana"}
+	for _, input := range inputs {
+		m.Match(input)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, input := range inputs {
+			m.Match(input)
+		}
+	})
+	a.Equal(t, 0.0, allocs)
+}
+
+// benchmarkSentences returns n product like sentences made out of a fixed set of generated words
+func benchmarkSentences(n int) []string {
+	r := rand.New(rand.NewSource(1))
+	syllables := []string{"ba", "ko", "ri", "ten", "lo", "mar", "vi", "su", "pe", "dan", "go", "xi"}
+
+	words := make([]string, 20_000)
+	for idx := range words {
+		word := ""
+		for syllable := 0; syllable < 2+r.Intn(3); syllable++ {
+			word += syllables[r.Intn(len(syllables))]
+		}
+		words[idx] = word
+	}
+
+	sentences := make([]string, n)
+	for idx := range sentences {
+		sentences[idx] = fmt.Sprintf("%s %s %s", words[r.Intn(len(words))], words[r.Intn(len(words))], words[r.Intn(len(words))])
+	}
+	return sentences
+}
+
+func BenchmarkEngines(b *testing.B) {
+	inputs := []string{
+		"i would like to buy a kobaten marvi and a susupe",
+		"nothing to see here",
+		"kobaten marvi lodan",
+	}
+
+	for _, size := range []int{1_000, 100_000, 1_000_000} {
+		if size > 100_000 && testing.Short() {
+			continue
+		}
+		for _, engine := range []struct {
+			name   string
+			engine Engine
+		}{
+			{"paths", EnginePaths},
+			{"trie", EngineTrie},
+		} {
+			b.Run(fmt.Sprintf("%s-%d", engine.name, size), func(b *testing.B) {
+				// The matcher is build here so only the selected benchmarks build one
+				m := NewMatcher(benchmarkSentences(size)...)
+				m.Engine = engine.engine
+				if engine.engine == EngineTrie {
+					// The trie is build on first use, do not count that
+					m.compiledTrie()
+				}
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					for _, input := range inputs {
+						m.Match(input)
+					}
+				}
+			})
+		}
+	}
+}